@@ -0,0 +1,60 @@
+package main
+
+import (
+	"strconv"
+	"strings"
+
+	"xiaohongshu-unified/xiaohongshu"
+)
+
+// parseInteractCount normalizes a Xiaohongshu interaction count string to an
+// int64. The site collapses large counts with a Chinese unit ("1.2万" ->
+// 12000) or an English k/w suffix ("3.4k" -> 3400); anything else is a
+// plain integer ("999" -> 999). An unparseable value is treated as 0 rather
+// than erroring, since a missing/zero count shouldn't fail the whole feed.
+func parseInteractCount(s string) int64 {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0
+	}
+
+	multiplier := 1.0
+	switch {
+	case strings.HasSuffix(s, "万"):
+		multiplier = 10000
+		s = strings.TrimSuffix(s, "万")
+	case strings.HasSuffix(s, "w"), strings.HasSuffix(s, "W"):
+		multiplier = 10000
+		s = s[:len(s)-1]
+	case strings.HasSuffix(s, "k"), strings.HasSuffix(s, "K"):
+		multiplier = 1000
+		s = s[:len(s)-1]
+	}
+
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0
+	}
+	return int64(f * multiplier)
+}
+
+// feedCounts pulls the like/collect/comment/share counts out of a note's
+// InteractInfo payload.
+func feedCounts(interact xiaohongshu.InteractInfo) (likes, collects, comments, shares int64) {
+	return parseInteractCount(interact.LikedCount),
+		parseInteractCount(interact.CollectedCount),
+		parseInteractCount(interact.CommentCount),
+		parseInteractCount(interact.ShareCount)
+}
+
+// feedContent extracts the note's body text and cover image URL, falling
+// back to the title when no description is present (e.g. in list views
+// that don't return the full note body).
+func feedContent(card xiaohongshu.NoteCard) (content, coverURL string) {
+	content = card.Desc
+	if content == "" {
+		content = card.DisplayTitle
+	}
+	coverURL = card.Cover.URLDefault
+	return content, coverURL
+}
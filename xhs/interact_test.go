@@ -0,0 +1,25 @@
+package main
+
+import "testing"
+
+func TestParseInteractCount(t *testing.T) {
+	cases := []struct {
+		in   string
+		want int64
+	}{
+		{"1.2万", 12000},
+		{"3.4k", 3400},
+		{"3.4K", 3400},
+		{"1.2w", 12000},
+		{"1.2W", 12000},
+		{"999", 999},
+		{"", 0},
+		{"赞", 0},
+	}
+
+	for _, c := range cases {
+		if got := parseInteractCount(c.in); got != c.want {
+			t.Errorf("parseInteractCount(%q) = %d, want %d", c.in, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+// Package admin exposes a small HTTP API for triggering and approving
+// orchestrator runs on demand, separate from the regular content
+// generation API - mainly so a human can preview a run's title and cover
+// before it posts when the orchestrator is running in preview-only mode.
+package admin
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"xiaohongshu-unified/internal/orchestrator"
+)
+
+// Server serves the admin API over HTTP, guarded by a bearer token.
+type Server struct {
+	orch   *orchestrator.Orchestrator
+	token  string
+	router *gin.Engine
+}
+
+// NewServer creates a new admin API server. token is the bearer token
+// required on every request; an empty token disables auth (local/dev use).
+func NewServer(orch *orchestrator.Orchestrator, token string) *Server {
+	s := &Server{
+		orch:  orch,
+		token: token,
+	}
+	s.router = s.setupRoutes()
+	return s
+}
+
+// Start starts the admin HTTP server on addr, blocking until it exits.
+func (s *Server) Start(addr string) error {
+	return s.router.Run(addr)
+}
+
+// setupRoutes sets up the router configuration.
+func (s *Server) setupRoutes() *gin.Engine {
+	gin.SetMode(gin.ReleaseMode)
+
+	router := gin.New()
+	router.Use(gin.Logger())
+	router.Use(gin.Recovery())
+	router.Use(s.corsMiddleware())
+
+	router.GET("/health", s.healthHandler)
+
+	api := router.Group("/")
+	api.Use(s.authMiddleware())
+	{
+		api.POST("/run", s.handleRun)
+		api.POST("/preview", s.handlePreview)
+		api.GET("/status", s.handleStatus)
+		api.GET("/runs/:id", s.handleGetRun)
+		api.POST("/runs/:id/approve", s.handleApproveRun)
+	}
+
+	return router
+}
+
+// healthHandler handles health check requests.
+func (s *Server) healthHandler(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": "ok", "service": "xhs-admin"})
+}
+
+// corsMiddleware adds CORS headers, mirroring the cover-gen MCP server's.
+func (s *Server) corsMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Header("Access-Control-Allow-Origin", "*")
+		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
+		c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization")
+
+		if c.Request.Method == "OPTIONS" {
+			c.AbortWithStatus(http.StatusNoContent)
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// authMiddleware requires "Authorization: Bearer <token>" to match the
+// configured admin token. If no token is configured, auth is skipped -
+// operators are expected to only bind Admin.Addr on a trusted network in
+// that case.
+func (s *Server) authMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if s.token == "" {
+			c.Next()
+			return
+		}
+
+		header := c.GetHeader("Authorization")
+		if !strings.HasPrefix(header, "Bearer ") || strings.TrimPrefix(header, "Bearer ") != s.token {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "missing or invalid bearer token"})
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// handleRun triggers a full run (gather, generate, cover, post) and
+// returns its result once finished.
+func (s *Server) handleRun(c *gin.Context) {
+	if err := s.orch.Run(c.Request.Context()); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "success"})
+}
+
+// handlePreview triggers a preview run (gather, generate, cover - no
+// posting) and returns its WorkflowResult, including the RunID needed to
+// approve it afterwards.
+func (s *Server) handlePreview(c *gin.Context) {
+	result, err := s.orch.Preview(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// handleStatus returns the status of all upstream services.
+func (s *Server) handleStatus(c *gin.Context) {
+	c.JSON(http.StatusOK, s.orch.GetServiceStatus())
+}
+
+// handleGetRun returns the persisted checkpoint for a given run ID.
+func (s *Server) handleGetRun(c *gin.Context) {
+	cp, err := s.orch.GetCheckpoint(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, cp)
+}
+
+// handleApproveRun posts a previously previewed run by ID.
+func (s *Server) handleApproveRun(c *gin.Context) {
+	result, err := s.orch.Approve(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
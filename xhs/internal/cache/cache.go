@@ -0,0 +1,85 @@
+// Package cache provides a small TTL cache shared by the orchestrator's
+// upstream-data services (weather, traffic, visitor), plus a cron-driven
+// scheduler that replays cached digests before they expire so a scheduled
+// workflow run never blocks on a cold upstream.
+package cache
+
+import (
+	"sync"
+	"time"
+)
+
+// Store is a TTL cache keyed by a caller-defined digest string (e.g.
+// "city|date" for weather, "date" for visitor). It also counts hits/misses
+// so GetServiceStatus can report a per-source cache hit rate.
+type Store struct {
+	ttl   time.Duration
+	items sync.Map // digest -> entry
+
+	mu     sync.Mutex
+	hits   int
+	misses int
+}
+
+type entry struct {
+	value   interface{}
+	expires time.Time
+}
+
+// NewStore creates a Store whose entries expire ttl after being Set.
+func NewStore(ttl time.Duration) *Store {
+	return &Store{ttl: ttl}
+}
+
+// Get returns the cached value for digest, if present and not expired.
+func (s *Store) Get(digest string) (interface{}, bool) {
+	if v, ok := s.items.Load(digest); ok {
+		if e := v.(entry); !time.Now().After(e.expires) {
+			s.recordHit()
+			return e.value, true
+		}
+		s.items.Delete(digest)
+	}
+	s.recordMiss()
+	return nil, false
+}
+
+// Set stores value under digest, resetting its TTL.
+func (s *Store) Set(digest string, value interface{}) {
+	s.items.Store(digest, entry{value: value, expires: time.Now().Add(s.ttl)})
+}
+
+// Digests returns every digest currently stored, so a PrefetchScheduler can
+// replay them against the live source before they expire.
+func (s *Store) Digests() []string {
+	var digests []string
+	s.items.Range(func(k, _ interface{}) bool {
+		digests = append(digests, k.(string))
+		return true
+	})
+	return digests
+}
+
+func (s *Store) recordHit() {
+	s.mu.Lock()
+	s.hits++
+	s.mu.Unlock()
+}
+
+func (s *Store) recordMiss() {
+	s.mu.Lock()
+	s.misses++
+	s.mu.Unlock()
+}
+
+// HitRate returns the fraction of Get calls served from cache since the
+// Store was created, or 0 if Get has never been called.
+func (s *Store) HitRate() float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	total := s.hits + s.misses
+	if total == 0 {
+		return 0
+	}
+	return float64(s.hits) / float64(total)
+}
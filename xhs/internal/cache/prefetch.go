@@ -0,0 +1,105 @@
+package cache
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// Refresher re-fetches and re-caches the value for a previously-seen
+// digest, using whichever Get* method originally produced it.
+type Refresher func(digest string) error
+
+// Source pairs a Store with the Refresher that can repopulate it.
+type Source struct {
+	Name    string
+	Store   *Store
+	Refresh Refresher
+}
+
+// PrefetchScheduler replays every registered Source's most recent request
+// digests a configurable lead time before each interval boundary, mirroring
+// wttr.in's pre-top-of-hour cache warming cron: a scheduled workflow run
+// finds a warm cache even if the upstream source is briefly unreachable
+// right at boundary time.
+type PrefetchScheduler struct {
+	cron     *cron.Cron
+	sources  []Source
+	interval time.Duration
+	lead     time.Duration
+}
+
+// NewPrefetchScheduler creates a scheduler that fires lead before every
+// boundary of interval (e.g. interval=24h, lead=5m fires daily at 23:55).
+func NewPrefetchScheduler(interval, lead time.Duration) *PrefetchScheduler {
+	return &PrefetchScheduler{
+		cron:     cron.New(),
+		interval: interval,
+		lead:     lead,
+	}
+}
+
+// Register adds a Source to be replayed on every tick.
+func (p *PrefetchScheduler) Register(src Source) {
+	p.sources = append(p.sources, src)
+}
+
+// Start schedules the prefetch tick and starts the underlying cron engine.
+func (p *PrefetchScheduler) Start() error {
+	spec, err := p.cronSpec()
+	if err != nil {
+		return err
+	}
+	if _, err := p.cron.AddFunc(spec, p.tick); err != nil {
+		return fmt.Errorf("failed to schedule prefetch: %w", err)
+	}
+	p.cron.Start()
+	return nil
+}
+
+// Stop stops the underlying cron engine.
+func (p *PrefetchScheduler) Stop() {
+	p.cron.Stop()
+}
+
+// cronSpec converts interval/lead into a standard 5-field cron expression
+// that fires lead minutes before every Nth-hour boundary. Only interval
+// values that are a whole number of hours are supported, which covers this
+// project's Settings.PostInterval values ("1h", "2h", "24h", ...).
+func (p *PrefetchScheduler) cronSpec() (string, error) {
+	hours := int(p.interval.Hours())
+	if hours <= 0 || p.interval%time.Hour != 0 {
+		return "", fmt.Errorf("unsupported prefetch interval %v: must be a whole number of hours", p.interval)
+	}
+
+	leadMinutes := int(p.lead.Minutes())
+	minute := 60 - leadMinutes
+	// Boundaries fall on hours 0, N, 2N, ... of the day; with a lead the
+	// tick lands in the hour before each of them.
+	startHour := hours - 1
+	if leadMinutes <= 0 {
+		minute = 0
+		startHour = 0
+	}
+
+	if hours >= 24 {
+		return fmt.Sprintf("%d %d * * *", minute, startHour%24), nil
+	}
+	return fmt.Sprintf("%d %d-23/%d * * *", minute, startHour, hours), nil
+}
+
+// tick replays every registered Source's cached digests through its
+// Refresher. A Source with nothing cached yet simply has nothing to
+// replay; a Refresher failure is logged and otherwise ignored since the
+// next live call will retry anyway.
+func (p *PrefetchScheduler) tick() {
+	for _, src := range p.sources {
+		for _, digest := range src.Store.Digests() {
+			if err := src.Refresh(digest); err != nil {
+				log.Printf("⚠️ prefetch: failed to refresh %s[%s]: %v", src.Name, digest, err)
+			}
+		}
+	}
+}
@@ -3,28 +3,40 @@ package config
 import (
 	"encoding/json"
 	"fmt"
+	"net/url"
 	"os"
+	"path/filepath"
+	"reflect"
+	"time"
 )
 
 // Config holds all configuration for the application
 type Config struct {
 	// Weather API configuration
 	WeatherAPI struct {
-		APIKey  string `json:"api_key"`
-		BaseURL string `json:"base_url"`
-		City    string `json:"city"`
+		APIKey          string  `json:"api_key" sensitive:"true"`
+		BaseURL         string  `json:"base_url"`
+		City            string  `json:"city"`
+		Provider        string  `json:"provider"` // "html" (default), "openweathermap", "caiyun", "amap", "baidu", or "multi"
+		Adcode          string  `json:"adcode"`   // AMap administrative division code
+		Lon             float64 `json:"lon"`      // used by Caiyun
+		Lat             float64 `json:"lat"`      // used by Caiyun
+		CacheTTLSeconds int     `json:"cache_ttl_seconds"` // response cache TTL, keyed by (city, date)
 	} `json:"weather_api"`
 
 	// Traffic API configuration
 	TrafficAPI struct {
-		APIKey  string `json:"api_key"`
-		BaseURL string `json:"base_url"`
-		City    string `json:"city"`
+		APIKey          string `json:"api_key" sensitive:"true"`
+		BaseURL         string `json:"base_url"`
+		City            string `json:"city"`
+		Provider        string `json:"provider"`          // "amap", "baidu", or "mock" (default)
+		Rectangle       string `json:"rectangle"`          // AMap bounding box: "lon1,lat1;lon2,lat2"
+		CacheTTLSeconds int    `json:"cache_ttl_seconds"` // response cache TTL, keyed by (provider, city)
 	} `json:"traffic_api"`
 
 	// DeepSeek LLM configuration
 	DeepSeekLLM struct {
-		APIKey  string `json:"api_key"`
+		APIKey  string `json:"api_key" sensitive:"true"`
 		BaseURL string `json:"base_url"`
 		Model   string `json:"model"`
 	} `json:"deepseek_llm"`
@@ -35,6 +47,14 @@ type Config struct {
 		Headless  bool   `json:"headless"`
 		BaseURL   string `json:"base_url"`
 		OutDir    string `json:"out_dir"`
+		Backend   string `json:"backend"` // "mcp" (default), "local", or "llm" - see covergen.Backend
+		// Transport selects how the "mcp" backend talks to the cover-gen MCP
+		// server: "sse" (default, HTTP POST to ServerURL with a
+		// text/event-stream upgrade) or "stdio" (spawn Command/Args and speak
+		// newline-delimited JSON-RPC over its stdin/stdout).
+		Transport string   `json:"transport"`
+		Command   string   `json:"command"` // stdio only: path to the MCP server binary
+		Args      []string `json:"args"`    // stdio only: arguments passed to Command
 	} `json:"mcp"`
 
 	// Xiaohongshu configuration
@@ -45,27 +65,76 @@ type Config struct {
 
 	// Weibo configuration
 	Weibo struct {
+		Enabled bool   `json:"enabled"`
 		UID     string `json:"uid"`
-		Cookies string `json:"cookies"`
-		Token   string `json:"token"`
+		Cookies string `json:"cookies" sensitive:"true"`
+		Token   string `json:"token" sensitive:"true"`
 	} `json:"weibo"`
 
+	// Visitor data source configuration
+	Visitor struct {
+		Source  string `json:"source"`   // "mock" (default), "http", or "csv"
+		APIURL  string `json:"api_url"`  // HTTPS JSON endpoint, queried as "<api_url>?date=YYYY-MM-DD"
+		APIKey  string `json:"api_key" sensitive:"true"` // optional bearer token for APIURL
+		CSVPath string `json:"csv_path"` // local CSV fallback with "date,count" rows
+		DBPath  string `json:"db_path"`  // SQLite file persisting daily history for trend charts
+	} `json:"visitor"`
+
 	// General settings
 	Settings struct {
-		PostInterval string `json:"post_interval"` // e.g., "1h", "24h"
-		LogLevel     string `json:"log_level"`
+		PostInterval        string `json:"post_interval"` // e.g., "1h", "24h"
+		LogLevel            string `json:"log_level"`
+		CacheTTLSeconds     int    `json:"cache_ttl_seconds"`     // default response cache TTL for services without their own (visitor, weibo)
+		PrefetchLeadMinutes int    `json:"prefetch_lead_minutes"` // minutes before each PostInterval boundary to warm caches
+		StateDir            string `json:"state_dir"`             // directory for the workflow checkpoint store
 	} `json:"settings"`
+
+	// Admin API configuration - a small HTTP API for triggering/previewing
+	// orchestrator runs on demand, separate from the regular content APIs
+	Admin struct {
+		Addr  string `json:"addr"`  // e.g. ":8091"; empty disables the admin API
+		Token string `json:"token" sensitive:"true"` // required bearer token for all admin endpoints
+	} `json:"admin"`
+
+	// Tracing configuration for the OpenTelemetry/Jaeger integration
+	Tracing struct {
+		Enabled      bool   `json:"enabled"`
+		ServiceName  string `json:"service_name"`
+		OTLPEndpoint string `json:"otlp_endpoint"` // Jaeger's OTLP gRPC receiver, e.g. "localhost:4317"
+	} `json:"tracing"`
 }
 
-// Load loads configuration from config.json file or environment variables
+// ResolvePath decides which config file Load should read: an explicit
+// -config flag value wins, then the CONFIG_FILE environment variable, then
+// the "config.json" default.
+func ResolvePath(flagValue string) string {
+	if flagValue != "" {
+		return flagValue
+	}
+	if env := os.Getenv("CONFIG_FILE"); env != "" {
+		return env
+	}
+	return "config.json"
+}
+
+// Load loads configuration from config.json (or CONFIG_FILE, if set) and
+// environment variables. Use LoadFile directly to honor an explicit -config
+// flag.
 func Load() (*Config, error) {
+	return LoadFile(ResolvePath(""))
+}
+
+// LoadFile loads configuration from path, then layers environment variable
+// overrides, defaults, and validation on top, in that order.
+func LoadFile(path string) (*Config, error) {
 	cfg := &Config{}
 
-	// Try to load from config.json first
-	if data, err := os.ReadFile("config.json"); err == nil {
+	if data, err := os.ReadFile(path); err == nil {
 		if err := json.Unmarshal(data, cfg); err != nil {
-			return nil, fmt.Errorf("failed to parse config.json: %w", err)
+			return nil, fmt.Errorf("failed to parse %s: %w", path, err)
 		}
+	} else if !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
 	}
 
 	// Override with environment variables if present
@@ -75,7 +144,7 @@ func Load() (*Config, error) {
 	setDefaults(cfg)
 
 	// Validate configuration
-	if err := validate(cfg); err != nil {
+	if err := Validate(cfg); err != nil {
 		return nil, fmt.Errorf("configuration validation failed: %w", err)
 	}
 
@@ -120,6 +189,9 @@ func loadFromEnv(cfg *Config) {
 		cfg.Xiaohongshu.ServerURL = url
 	}
 
+	if enabled := os.Getenv("WEIBO_ENABLED"); enabled == "true" {
+		cfg.Weibo.Enabled = true
+	}
 	if uid := os.Getenv("WEIBO_UID"); uid != "" {
 		cfg.Weibo.UID = uid
 	}
@@ -136,9 +208,30 @@ func loadFromEnv(cfg *Config) {
 	if logLevel := os.Getenv("LOG_LEVEL"); logLevel != "" {
 		cfg.Settings.LogLevel = logLevel
 	}
+
+	if addr := os.Getenv("ADMIN_ADDR"); addr != "" {
+		cfg.Admin.Addr = addr
+	}
+	if token := os.Getenv("ADMIN_TOKEN"); token != "" {
+		cfg.Admin.Token = token
+	}
+
+	if enabled := os.Getenv("TRACING_ENABLED"); enabled == "true" {
+		cfg.Tracing.Enabled = true
+	}
+	if name := os.Getenv("TRACING_SERVICE_NAME"); name != "" {
+		cfg.Tracing.ServiceName = name
+	}
+	if endpoint := os.Getenv("TRACING_OTLP_ENDPOINT"); endpoint != "" {
+		cfg.Tracing.OTLPEndpoint = endpoint
+	}
 }
 
-// setDefaults sets default values for configuration
+// setDefaults sets default values for configuration. It never fills in
+// real credentials: anything secret (Weibo cookies/token, API keys) is left
+// empty here and caught by Validate instead, so a missing secret fails
+// loudly rather than silently falling back to a stale value baked into the
+// binary.
 func setDefaults(cfg *Config) {
 	if cfg.WeatherAPI.BaseURL == "" {
 		cfg.WeatherAPI.BaseURL = "https://api.openweathermap.org/data/2.5"
@@ -165,7 +258,17 @@ func setDefaults(cfg *Config) {
 		cfg.MCP.BaseURL = "http://localhost:3000"
 	}
 	if cfg.MCP.OutDir == "" {
-		cfg.MCP.OutDir = "/Users/kx/Desktop"
+		if dir, err := os.UserCacheDir(); err == nil {
+			cfg.MCP.OutDir = filepath.Join(dir, "xiaohongshu-unified", "covers")
+		} else {
+			cfg.MCP.OutDir = "./covers"
+		}
+	}
+	if cfg.MCP.Backend == "" {
+		cfg.MCP.Backend = "mcp"
+	}
+	if cfg.MCP.Transport == "" {
+		cfg.MCP.Transport = "sse"
 	}
 	// Default headless to false for MCP
 	cfg.MCP.Headless = false
@@ -176,15 +279,11 @@ func setDefaults(cfg *Config) {
 	// Default headless to false for Xiaohongshu
 	cfg.Xiaohongshu.Headless = false
 
-	// Set default weibo configuration
-	if cfg.Weibo.UID == "" {
-		cfg.Weibo.UID = "3937775216"
+	if cfg.Visitor.Source == "" {
+		cfg.Visitor.Source = "mock"
 	}
-	if cfg.Weibo.Cookies == "" {
-		cfg.Weibo.Cookies = "SUB=_2AkMfzfZxf8NxqwFRmfscymjibox_zA3EieKpkQeqJRMxHRl-yT9kqnIitRB6NE3Ynp3g3XUjfERDfRvDu2Ob-V0AV-Ht; XSRF-TOKEN=JVS9su9p3gsRZyDzgsijAdx5; WBPSESS=gJ7ElPMf_3q2cdj5JUfmvNSXzQofuuhpbfKWU-JmetuhhFVlp1s7T3D6PJClzn45urDFp34oVajUL4N7sYweJyZs74npFsMnIJ9PUcbSjV9Pwg5IdiwWIEUuHTqDSRsJ3pCe78X7Zm38ENkYYoFzAwkxKSCkNQ3Kb-j9COTqz14="
-	}
-	if cfg.Weibo.Token == "" {
-		cfg.Weibo.Token = "JVS9su9p3gsRZyDzgsijAdx5"
+	if cfg.Visitor.DBPath == "" {
+		cfg.Visitor.DBPath = "visitor_history.db"
 	}
 
 	if cfg.Settings.PostInterval == "" {
@@ -193,16 +292,127 @@ func setDefaults(cfg *Config) {
 	if cfg.Settings.LogLevel == "" {
 		cfg.Settings.LogLevel = "info"
 	}
+	if cfg.Settings.PrefetchLeadMinutes == 0 {
+		cfg.Settings.PrefetchLeadMinutes = 5
+	}
+	if cfg.Settings.StateDir == "" {
+		cfg.Settings.StateDir = "./state"
+	}
+
+	if cfg.Tracing.ServiceName == "" {
+		cfg.Tracing.ServiceName = "xiaohongshu-unified"
+	}
+	if cfg.Tracing.OTLPEndpoint == "" {
+		cfg.Tracing.OTLPEndpoint = "localhost:4317"
+	}
 }
 
-// validate validates the configuration
-func validate(cfg *Config) error {
+// Validate checks cfg for the handful of fields that can't just take a zero
+// value: required secrets, URL-shaped fields, parseable durations, and a
+// writable output directory. It runs after setDefaults, so anything with a
+// sane default never reaches here unset.
+func Validate(cfg *Config) error {
 	if cfg.WeatherAPI.APIKey == "" {
 		return fmt.Errorf("weather API key is required")
 	}
 	if cfg.DeepSeekLLM.APIKey == "" {
 		return fmt.Errorf("DeepSeek API key is required")
 	}
+
+	if cfg.Weibo.Enabled {
+		if cfg.Weibo.Cookies == "" {
+			return fmt.Errorf("weibo.cookies is required when weibo is enabled")
+		}
+		if cfg.Weibo.Token == "" {
+			return fmt.Errorf("weibo.token is required when weibo is enabled")
+		}
+	}
+
+	baseURLs := map[string]string{
+		"weather_api.base_url": cfg.WeatherAPI.BaseURL,
+		"traffic_api.base_url": cfg.TrafficAPI.BaseURL,
+		"deepseek_llm.base_url": cfg.DeepSeekLLM.BaseURL,
+		"mcp.base_url":          cfg.MCP.BaseURL,
+	}
+	for field, value := range baseURLs {
+		if err := validateURL(field, value); err != nil {
+			return err
+		}
+	}
+
+	if _, err := time.ParseDuration(cfg.Settings.PostInterval); err != nil {
+		return fmt.Errorf("settings.post_interval %q is not a valid duration: %w", cfg.Settings.PostInterval, err)
+	}
+
+	if err := validateWritableDir(cfg.MCP.OutDir); err != nil {
+		return fmt.Errorf("mcp.out_dir %q is not writable: %w", cfg.MCP.OutDir, err)
+	}
+
 	return nil
 }
 
+func validateURL(field, value string) error {
+	if value == "" {
+		return fmt.Errorf("%s is required", field)
+	}
+	u, err := url.Parse(value)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return fmt.Errorf("%s %q is not a valid absolute URL", field, value)
+	}
+	return nil
+}
+
+// validateWritableDir creates dir (and any parents) if needed, then checks
+// that a file can actually be written into it.
+func validateWritableDir(dir string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	probe := filepath.Join(dir, ".write_test")
+	if err := os.WriteFile(probe, []byte{}, 0600); err != nil {
+		return err
+	}
+	return os.Remove(probe)
+}
+
+const redactedPlaceholder = "[REDACTED]"
+
+// String renders cfg as JSON with every field tagged sensitive:"true"
+// blanked out, so logging or debug-printing a Config never leaks API keys,
+// cookies, or tokens.
+func (cfg *Config) String() string {
+	data, err := cfg.MarshalJSON()
+	if err != nil {
+		return fmt.Sprintf("<config: %v>", err)
+	}
+	return string(data)
+}
+
+// MarshalJSON redacts sensitive fields before encoding, so callers that
+// json.Marshal a Config (e.g. a debug endpoint) get the same redaction as
+// String().
+func (cfg *Config) MarshalJSON() ([]byte, error) {
+	redacted := redactSensitive(reflect.ValueOf(*cfg)).Interface()
+	return json.Marshal(redacted)
+}
+
+// redactSensitive returns a copy of v with every string field tagged
+// sensitive:"true" replaced by redactedPlaceholder, recursing into nested
+// (anonymous) structs.
+func redactSensitive(v reflect.Value) reflect.Value {
+	out := reflect.New(v.Type()).Elem()
+	out.Set(v)
+
+	for i := 0; i < v.NumField(); i++ {
+		field := out.Field(i)
+		switch field.Kind() {
+		case reflect.Struct:
+			field.Set(redactSensitive(v.Field(i)))
+		case reflect.String:
+			if v.Type().Field(i).Tag.Get("sensitive") == "true" && field.String() != "" {
+				field.SetString(redactedPlaceholder)
+			}
+		}
+	}
+	return out
+}
@@ -0,0 +1,69 @@
+package covergen
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"xiaohongshu-unified/internal/config"
+)
+
+// ImageRequest describes a single image-generation call. Not every field is
+// used by every Backend: the MCP backend only cares about CoverText/
+// OverlayPNG/BaseAsset, the LLM backend only about Prompt/Style/Size/Quality,
+// and the local backend drives entirely off BaseAsset/Pipeline.
+type ImageRequest struct {
+	Prompt     string                 `json:"prompt"`               // Image generation prompt
+	Style      string                 `json:"style,omitempty"`      // Image style (optional)
+	Size       string                 `json:"size,omitempty"`       // Image size (optional)
+	Quality    string                 `json:"quality,omitempty"`    // Image quality (optional)
+	Parameters map[string]interface{} `json:"parameters,omitempty"` // Additional parameters
+
+	// CoverText is the text overlaid on the generated cover (MCP backend).
+	CoverText string `json:"cover_text,omitempty"`
+	// OverlayPNG is an optional PNG (e.g. a visitor trend chart) drawn on
+	// top of the cover as a data URI.
+	OverlayPNG []byte `json:"-"`
+
+	// BaseAsset is the source image the local backend's Pipeline runs
+	// against (the MCP backend renders onto it too). The MCP backend treats
+	// empty as "pick a random asset from the MCP assets API"; the local
+	// backend rejects an empty BaseAsset.
+	BaseAsset string `json:"base_asset,omitempty"`
+	// Pipeline is a declarative, Tencent CI PicOperations-style chain of
+	// Rules applied in order to BaseAsset by the local backend.
+	Pipeline []Rule `json:"pipeline,omitempty"`
+}
+
+// ImageResponse represents the response from image generation
+type ImageResponse struct {
+	ImageURL    string    `json:"image_url"`      // Generated image URL
+	ImagePath   string    `json:"image_path"`     // Local image path
+	ImageData   string    `json:"image_data"`     // Base64 encoded image data
+	Prompt      string    `json:"prompt"`         // Used prompt
+	GeneratedAt time.Time `json:"generated_at"`
+	Error       string    `json:"error,omitempty"` // Error message if any
+}
+
+// Backend generates a single image from an ImageRequest. Concrete
+// implementations differ only in *how* the pixels get produced: delegating
+// to the cover-gen MCP server, calling a DeepSeek/OpenAI-compatible image
+// endpoint directly, or running a local Rule pipeline over a base asset.
+type Backend interface {
+	Name() string
+	Generate(ctx context.Context, req *ImageRequest) (*ImageResponse, error)
+}
+
+// NewBackend selects a Backend by name: "mcp" (default), "local", or "llm".
+func NewBackend(name string, cfg *config.Config) (Backend, error) {
+	switch name {
+	case "", "mcp":
+		return newMCPBackend(cfg), nil
+	case "local":
+		return newLocalBackend(cfg), nil
+	case "llm":
+		return newLLMBackend(cfg), nil
+	default:
+		return nil, fmt.Errorf("unknown cover-gen backend %q", name)
+	}
+}
@@ -0,0 +1,103 @@
+package covergen
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"xiaohongshu-unified/internal/config"
+)
+
+// llmBackend generates an image by calling an OpenAI-compatible
+// "/v1/images/generations" endpoint directly (DeepSeek's image models speak
+// the same dialect), skipping the cover-gen MCP server entirely.
+type llmBackend struct {
+	cfg        *config.Config
+	httpClient *http.Client
+}
+
+func newLLMBackend(cfg *config.Config) *llmBackend {
+	return &llmBackend{
+		cfg:        cfg,
+		httpClient: &http.Client{Timeout: 120 * time.Second},
+	}
+}
+
+func (b *llmBackend) Name() string { return "llm" }
+
+// imageGenerationRequest is the OpenAI-compatible request body.
+type imageGenerationRequest struct {
+	Model   string `json:"model"`
+	Prompt  string `json:"prompt"`
+	Size    string `json:"size,omitempty"`
+	Quality string `json:"quality,omitempty"`
+	N       int    `json:"n"`
+}
+
+// imageGenerationResponse is the OpenAI-compatible response body.
+type imageGenerationResponse struct {
+	Data []struct {
+		URL     string `json:"url"`
+		B64JSON string `json:"b64_json"`
+	} `json:"data"`
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
+func (b *llmBackend) Generate(ctx context.Context, req *ImageRequest) (*ImageResponse, error) {
+	size := req.Size
+	if size == "" {
+		size = "1024x1024"
+	}
+
+	body := imageGenerationRequest{
+		Model:   b.cfg.DeepSeekLLM.Model,
+		Prompt:  req.Prompt,
+		Size:    size,
+		Quality: req.Quality,
+		N:       1,
+	}
+	reqBody, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal image generation request: %w", err)
+	}
+
+	apiURL := b.cfg.DeepSeekLLM.BaseURL + "/v1/images/generations"
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", apiURL, bytes.NewBuffer(reqBody))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create image generation request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Authorization", "Bearer "+b.cfg.DeepSeekLLM.APIKey)
+
+	resp, err := b.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call image generation endpoint: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var imgResp imageGenerationResponse
+	if err := json.NewDecoder(resp.Body).Decode(&imgResp); err != nil {
+		return nil, fmt.Errorf("failed to decode image generation response: %w", err)
+	}
+	if imgResp.Error != nil {
+		return nil, fmt.Errorf("image generation failed: %s", imgResp.Error.Message)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("image generation endpoint returned status %d", resp.StatusCode)
+	}
+	if len(imgResp.Data) == 0 {
+		return nil, fmt.Errorf("image generation endpoint returned no images")
+	}
+
+	return &ImageResponse{
+		ImageURL:    imgResp.Data[0].URL,
+		ImageData:   imgResp.Data[0].B64JSON,
+		Prompt:      req.Prompt,
+		GeneratedAt: time.Now(),
+	}, nil
+}
@@ -0,0 +1,242 @@
+package covergen
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+
+	"xiaohongshu-unified/internal/config"
+)
+
+// localBackend runs req.Pipeline against req.BaseAsset entirely in-process,
+// without a browser or a remote MCP server - a Go port of Tencent Cloud
+// CI's PicOperations/PicOperationsRules idea: a short chain of declarative
+// Rules (resize, crop, watermark, blur, format) applied in order.
+type localBackend struct {
+	cfg        *config.Config
+	httpClient *http.Client
+}
+
+func newLocalBackend(cfg *config.Config) *localBackend {
+	return &localBackend{
+		cfg:        cfg,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+func (b *localBackend) Name() string { return "local" }
+
+func (b *localBackend) Generate(ctx context.Context, req *ImageRequest) (*ImageResponse, error) {
+	if req.BaseAsset == "" {
+		return nil, fmt.Errorf("local backend requires req.BaseAsset")
+	}
+	for _, rule := range req.Pipeline {
+		if err := rule.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid pipeline: %w", err)
+		}
+	}
+
+	img, err := b.loadAsset(ctx, req.BaseAsset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load base asset %q: %w", req.BaseAsset, err)
+	}
+
+	format := "jpeg"
+	for _, rule := range req.Pipeline {
+		img, format, err = applyRule(img, rule, format)
+		if err != nil {
+			return nil, fmt.Errorf("pipeline rule %q failed: %w", rule.Type, err)
+		}
+	}
+
+	outputPath := b.outputPath(format)
+	if err := saveImage(img, format, outputPath); err != nil {
+		return nil, fmt.Errorf("failed to save generated image: %w", err)
+	}
+
+	return &ImageResponse{
+		ImagePath:   outputPath,
+		ImageURL:    outputPath,
+		Prompt:      req.Prompt,
+		GeneratedAt: time.Now(),
+	}, nil
+}
+
+// loadAsset fetches asset over HTTP if it looks like a URL, otherwise reads
+// it as a local file path.
+func (b *localBackend) loadAsset(ctx context.Context, asset string) (image.Image, error) {
+	var r io.Reader
+	if strings.HasPrefix(asset, "http://") || strings.HasPrefix(asset, "https://") {
+		httpReq, err := http.NewRequestWithContext(ctx, "GET", asset, nil)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := b.httpClient.Do(httpReq)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("status %d", resp.StatusCode)
+		}
+		r = resp.Body
+	} else {
+		f, err := os.Open(asset)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		r = f
+	}
+
+	img, _, err := image.Decode(r)
+	return img, err
+}
+
+func (b *localBackend) outputPath(format string) string {
+	ext := "jpeg"
+	if format == "webp" {
+		ext = "webp"
+	}
+	return fmt.Sprintf("%s/cover_%s.%s", b.cfg.MCP.OutDir, time.Now().Format("20060102_150405"), ext)
+}
+
+// applyRule applies a single pipeline Rule to img, returning the resulting
+// image and the (possibly updated, by a "format" rule) output format.
+func applyRule(img image.Image, rule Rule, format string) (image.Image, string, error) {
+	switch rule.Type {
+	case RuleResize:
+		return resize(img, rule.Width, rule.Height), format, nil
+	case RuleCrop:
+		return crop(img, rule.X, rule.Y, rule.Width, rule.Height), format, nil
+	case RuleWatermark:
+		return watermark(img, rule.Text, rule.X, rule.Y, rule.Color), format, nil
+	case RuleBlur:
+		return boxBlur(img, rule.Radius), format, nil
+	case RuleFormat:
+		if rule.Format == "webp" {
+			return nil, "", fmt.Errorf("webp encoding is not yet supported, only decoding")
+		}
+		return img, rule.Format, nil
+	default:
+		return nil, "", fmt.Errorf("unknown rule type %q", rule.Type)
+	}
+}
+
+// resize scales img to width x height using nearest-neighbor sampling -
+// good enough for cover thumbnails without pulling in a resize library.
+func resize(img image.Image, width, height int) image.Image {
+	src := img.Bounds()
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		sy := src.Min.Y + y*src.Dy()/height
+		for x := 0; x < width; x++ {
+			sx := src.Min.X + x*src.Dx()/width
+			dst.Set(x, y, img.At(sx, sy))
+		}
+	}
+	return dst
+}
+
+// crop returns the width x height region of img starting at (x, y).
+func crop(img image.Image, x, y, width, height int) image.Image {
+	rect := image.Rect(x, y, x+width, y+height)
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(dst, dst.Bounds(), img, rect.Min, draw.Src)
+	return dst
+}
+
+// watermark draws text onto img at (x, y) in the given hex color (e.g.
+// "#ffffff"), using the stdlib's fixed-width basicfont face.
+func watermark(img image.Image, text string, x, y int, hexColor string) image.Image {
+	dst := image.NewRGBA(img.Bounds())
+	draw.Draw(dst, dst.Bounds(), img, image.Point{}, draw.Src)
+
+	c := parseHexColor(hexColor)
+	d := &font.Drawer{
+		Dst:  dst,
+		Src:  image.NewUniform(c),
+		Face: basicfont.Face7x13,
+		Dot:  fixed.P(x, y),
+	}
+	d.DrawString(text)
+	return dst
+}
+
+// boxBlur approximates a gaussian blur with a simple (2*radius+1)-wide box
+// blur, cheap enough for cover-sized images without an external dependency.
+func boxBlur(img image.Image, radius float64) image.Image {
+	r := int(radius)
+	if r < 1 {
+		r = 1
+	}
+	bounds := img.Bounds()
+	dst := image.NewRGBA(bounds)
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			var sr, sg, sb, sa, n uint32
+			for dy := -r; dy <= r; dy++ {
+				for dx := -r; dx <= r; dx++ {
+					px, py := x+dx, y+dy
+					if px < bounds.Min.X || px >= bounds.Max.X || py < bounds.Min.Y || py >= bounds.Max.Y {
+						continue
+					}
+					cr, cg, cb, ca := img.At(px, py).RGBA()
+					sr += cr
+					sg += cg
+					sb += cb
+					sa += ca
+					n++
+				}
+			}
+			dst.Set(x, y, color.RGBA64{
+				R: uint16(sr / n), G: uint16(sg / n), B: uint16(sb / n), A: uint16(sa / n),
+			})
+		}
+	}
+	return dst
+}
+
+func parseHexColor(s string) color.Color {
+	s = strings.TrimPrefix(s, "#")
+	if len(s) != 6 {
+		return color.White
+	}
+	var r, g, b uint8
+	fmt.Sscanf(s, "%02x%02x%02x", &r, &g, &b)
+	return color.RGBA{R: r, G: g, B: b, A: 255}
+}
+
+func saveImage(img image.Image, format, path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	switch format {
+	case "png":
+		return png.Encode(f, img)
+	default:
+		var buf bytes.Buffer
+		if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: 90}); err != nil {
+			return err
+		}
+		_, err := f.Write(buf.Bytes())
+		return err
+	}
+}
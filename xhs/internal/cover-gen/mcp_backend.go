@@ -0,0 +1,197 @@
+package covergen
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"xiaohongshu-unified/internal/config"
+)
+
+// mcpBackend is the original Backend: it delegates to the cover-gen MCP
+// server's generate_xiaohongshu_cover tool, picking a random base asset from
+// the server's /api/assets endpoint. The MCP connection itself (transport,
+// initialize handshake, tools/list) is opened lazily on first use, since
+// newMCPBackend has no context to hang a round-trip off of.
+type mcpBackend struct {
+	cfg        *config.Config
+	httpClient *http.Client
+
+	clientOnce sync.Once
+	client     *MCPClient
+	clientErr  error
+}
+
+func newMCPBackend(cfg *config.Config) *mcpBackend {
+	return &mcpBackend{
+		cfg: cfg,
+		httpClient: &http.Client{
+			Timeout: 120 * time.Second, // Image generation can take time
+		},
+	}
+}
+
+func (b *mcpBackend) Name() string { return "mcp" }
+
+// mcpClient returns the lazily-initialized MCPClient, performing the
+// transport connect/initialize/tools-list handshake on the first call and
+// caching the result (including any error) for every call after.
+func (b *mcpBackend) mcpClient(ctx context.Context) (*MCPClient, error) {
+	b.clientOnce.Do(func() {
+		b.client, b.clientErr = newMCPClient(ctx, b.cfg)
+	})
+	return b.client, b.clientErr
+}
+
+// AssetsResponse represents the response from the assets API
+type AssetsResponse struct {
+	Images []string `json:"images"`
+}
+
+// fetchAvailableAssets fetches the list of available assets from the cover service
+func (b *mcpBackend) fetchAvailableAssets(ctx context.Context) ([]string, error) {
+	url := b.cfg.MCP.BaseURL + "/api/assets"
+	httpReq, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create assets request: %w", err)
+	}
+
+	resp, err := b.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch assets: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch assets: status %d", resp.StatusCode)
+	}
+
+	var assetsResp AssetsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&assetsResp); err != nil {
+		return nil, fmt.Errorf("failed to decode assets response: %w", err)
+	}
+
+	return assetsResp.Images, nil
+}
+
+// selectRandomAsset selects a random asset from the available list
+func (b *mcpBackend) selectRandomAsset(ctx context.Context) (string, error) {
+	assets, err := b.fetchAvailableAssets(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	if len(assets) == 0 {
+		return "", fmt.Errorf("no assets available")
+	}
+
+	index := rand.Intn(len(assets))
+	return assets[index], nil
+}
+
+// generateOutputPath generates a timestamped output path in the configured directory
+func (b *mcpBackend) generateOutputPath() string {
+	timestamp := time.Now().Format("20060102_150405")
+	filename := fmt.Sprintf("cover_%s.jpeg", timestamp)
+	return filepath.Join(b.cfg.MCP.OutDir, filename)
+}
+
+// Generate renders req by calling the cover-gen MCP server's
+// generate_xiaohongshu_cover tool against a random (or req.BaseAsset)
+// source image.
+func (b *mcpBackend) Generate(ctx context.Context, req *ImageRequest) (*ImageResponse, error) {
+	coverText := req.CoverText
+	if coverText == "" {
+		coverText = "Sample Text"
+	}
+
+	asset := req.BaseAsset
+	if asset == "" {
+		var err error
+		asset, err = b.selectRandomAsset(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to select random asset: %w", err)
+		}
+	}
+
+	outputPath := b.generateOutputPath()
+
+	arguments := map[string]interface{}{
+		"baseUrl":         b.cfg.MCP.BaseURL,
+		"selector":        "#exportable",
+		"image":           asset,
+		"text":            coverText,
+		"output_path":     outputPath,
+		"headless":        b.cfg.MCP.Headless,
+		"fontFamily":      "Comic Sans MS",
+		"fontSize":        48,
+		"fontWeight":      "bold",
+		"color":           "#0e0d0c",
+		"backgroundColor": "#f4f750",
+		"textShadow":      "2px 2px 4px #000000",
+		"border":          "1px solidrgb(187, 23, 23)",
+		"borderRadius":    32,
+		"borderWidth":     2,
+		"borderStyle":     "dashed",
+		"padding":         40,
+		"scaleX":          1.0,
+		"scaleY":          1.0,
+		"skewX":           -15,
+		"skewY":           0.0,
+		"opacity":         0.8,
+		"overlayColor":    "#443c3c",
+		"x":               50,
+		"y":               50,
+	}
+	if len(req.OverlayPNG) > 0 {
+		arguments["overlayImage"] = "data:image/png;base64," + base64.StdEncoding.EncodeToString(req.OverlayPNG)
+	}
+
+	client, err := b.mcpClient(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to cover-gen MCP server: %w", err)
+	}
+
+	result, err := client.CallTool(ctx, "generate_xiaohongshu_cover", arguments)
+	if err != nil {
+		return nil, err
+	}
+	if result.IsError {
+		return nil, fmt.Errorf("generate_xiaohongshu_cover failed: %s", toolResultText(result))
+	}
+
+	return &ImageResponse{
+		ImagePath:   outputPath,
+		ImageURL:    outputPath,
+		Prompt:      coverText,
+		GeneratedAt: time.Now(),
+	}, nil
+}
+
+// Capabilities reports the MCP server's advertised tools, for
+// Client.GetCapabilities.
+func (b *mcpBackend) Capabilities(ctx context.Context) (map[string]interface{}, error) {
+	client, err := b.mcpClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{"tools": client.Tools()}, nil
+}
+
+// toolResultText concatenates a ToolResult's text content blocks, for
+// logging/error messages.
+func toolResultText(result *ToolResult) string {
+	text := ""
+	for _, c := range result.Content {
+		if c.Type == "text" {
+			text += c.Text
+		}
+	}
+	return text
+}
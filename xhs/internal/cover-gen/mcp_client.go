@@ -0,0 +1,233 @@
+package covergen
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"xiaohongshu-unified/internal/config"
+)
+
+// ToolContent is one block of a tools/call result, mirroring MCP's content
+// union (currently only "text" is produced by the cover-gen server, but the
+// other types round-trip untouched).
+type ToolContent struct {
+	Type     string `json:"type"`
+	Text     string `json:"text,omitempty"`
+	Data     string `json:"data,omitempty"`
+	MimeType string `json:"mimeType,omitempty"`
+}
+
+// ToolResult is the decoded result of a CallTool invocation.
+type ToolResult struct {
+	Content []ToolContent `json:"content"`
+	IsError bool          `json:"isError"`
+}
+
+// ToolSchema is one entry from the server's tools/list response.
+type ToolSchema struct {
+	Name        string                 `json:"name"`
+	Description string                 `json:"description"`
+	InputSchema map[string]interface{} `json:"inputSchema"`
+}
+
+// transport abstracts the two standard MCP transports (stdio and SSE)
+// behind a single blocking request/response call, so MCPClient doesn't care
+// which one is in use.
+type transport interface {
+	call(ctx context.Context, method string, params interface{}) (json.RawMessage, error)
+	close() error
+}
+
+// MCPClient is a conformant MCP client: it performs the initialize
+// handshake, caches the server's advertised tools via tools/list, and
+// validates arguments against a tool's schema before calling it.
+type MCPClient struct {
+	transport transport
+
+	mu    sync.Mutex
+	tools map[string]ToolSchema
+}
+
+// newMCPClient opens transport (per cfg.MCP.Transport), performs the
+// initialize handshake, and caches the server's tool schemas - failing fast
+// if any of that doesn't succeed, rather than deferring the error to the
+// first CallTool.
+func newMCPClient(ctx context.Context, cfg *config.Config) (*MCPClient, error) {
+	t, err := newTransport(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &MCPClient{transport: t, tools: map[string]ToolSchema{}}
+	if err := c.initialize(ctx); err != nil {
+		t.close()
+		return nil, fmt.Errorf("mcp initialize failed: %w", err)
+	}
+	if err := c.refreshTools(ctx); err != nil {
+		t.close()
+		return nil, fmt.Errorf("mcp tools/list failed: %w", err)
+	}
+	return c, nil
+}
+
+func newTransport(cfg *config.Config) (transport, error) {
+	switch cfg.MCP.Transport {
+	case "", "sse":
+		return newSSETransport(cfg.MCP.ServerURL), nil
+	case "stdio":
+		if cfg.MCP.Command == "" {
+			return nil, fmt.Errorf("mcp.command is required for the stdio transport")
+		}
+		return newStdioTransport(cfg.MCP.Command, cfg.MCP.Args)
+	default:
+		return nil, fmt.Errorf("unknown mcp transport %q", cfg.MCP.Transport)
+	}
+}
+
+// initialize performs MCP's required handshake before any other request.
+func (c *MCPClient) initialize(ctx context.Context) error {
+	params := map[string]interface{}{
+		"protocolVersion": "2024-11-05",
+		"capabilities":    map[string]interface{}{},
+		"clientInfo": map[string]interface{}{
+			"name":    "huanqiu-image-generator",
+			"version": "1.0.0",
+		},
+	}
+	_, err := c.transport.call(ctx, "initialize", params)
+	return err
+}
+
+// refreshTools re-fetches tools/list and replaces the cached tool schemas,
+// so tools added on the server become callable without a code change here.
+func (c *MCPClient) refreshTools(ctx context.Context) error {
+	raw, err := c.transport.call(ctx, "tools/list", nil)
+	if err != nil {
+		return err
+	}
+
+	var result struct {
+		Tools []ToolSchema `json:"tools"`
+	}
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return fmt.Errorf("failed to decode tools/list result: %w", err)
+	}
+
+	tools := make(map[string]ToolSchema, len(result.Tools))
+	for _, tool := range result.Tools {
+		tools[tool.Name] = tool
+	}
+
+	c.mu.Lock()
+	c.tools = tools
+	c.mu.Unlock()
+	return nil
+}
+
+// Tools returns the cached tool schemas from the last tools/list call.
+func (c *MCPClient) Tools() []ToolSchema {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	tools := make([]ToolSchema, 0, len(c.tools))
+	for _, tool := range c.tools {
+		tools = append(tools, tool)
+	}
+	return tools
+}
+
+// CallTool validates args against name's cached input schema, then invokes
+// tools/call. An unknown tool name (not in the last tools/list) fails
+// locally instead of round-tripping to the server.
+func (c *MCPClient) CallTool(ctx context.Context, name string, args map[string]interface{}) (*ToolResult, error) {
+	c.mu.Lock()
+	schema, ok := c.tools[name]
+	c.mu.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown tool %q (not in this server's tools/list)", name)
+	}
+	if err := validateArgs(schema.InputSchema, args); err != nil {
+		return nil, fmt.Errorf("invalid arguments for tool %q: %w", name, err)
+	}
+
+	raw, err := c.transport.call(ctx, "tools/call", map[string]interface{}{
+		"name":      name,
+		"arguments": args,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var result ToolResult
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, fmt.Errorf("failed to decode tools/call result: %w", err)
+	}
+	return &result, nil
+}
+
+// Close releases the underlying transport (closing the stdio subprocess, if
+// that's the one in use).
+func (c *MCPClient) Close() error {
+	return c.transport.close()
+}
+
+// validateArgs applies the subset of JSON Schema that cover-gen MCP servers
+// actually use in practice: required properties and each property's basic
+// type. It's intentionally not a full validator - the goal is catching
+// typos and missing fields locally, not enforcing the whole spec.
+func validateArgs(schema map[string]interface{}, args map[string]interface{}) error {
+	if schema == nil {
+		return nil
+	}
+
+	if required, ok := schema["required"].([]interface{}); ok {
+		for _, r := range required {
+			name, _ := r.(string)
+			if _, present := args[name]; !present {
+				return fmt.Errorf("missing required argument %q", name)
+			}
+		}
+	}
+
+	properties, _ := schema["properties"].(map[string]interface{})
+	for name, value := range args {
+		propSchema, ok := properties[name].(map[string]interface{})
+		if !ok {
+			continue // schema doesn't constrain this argument; let the server decide
+		}
+		wantType, _ := propSchema["type"].(string)
+		if wantType == "" || matchesJSONType(value, wantType) {
+			continue
+		}
+		return fmt.Errorf("argument %q should be %s, got %T", name, wantType, value)
+	}
+	return nil
+}
+
+// matchesJSONType reports whether value is a Go representation of a JSON
+// Schema "type" as produced by encoding/json (numbers decode to float64).
+func matchesJSONType(value interface{}, wantType string) bool {
+	switch wantType {
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "number", "integer":
+		switch value.(type) {
+		case int, int64, float64:
+			return true
+		}
+		return false
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "object":
+		_, ok := value.(map[string]interface{})
+		return ok
+	case "array":
+		_, ok := value.([]interface{})
+		return ok
+	default:
+		return true
+	}
+}
@@ -0,0 +1,69 @@
+package covergen
+
+import "fmt"
+
+// RuleType names a single step of a Pipeline, modeled on Tencent Cloud CI's
+// PicOperations/PicOperationsRules: a JSON-describable chain of operations
+// applied in order to one base asset.
+type RuleType string
+
+const (
+	RuleResize    RuleType = "resize"
+	RuleCrop      RuleType = "crop"
+	RuleWatermark RuleType = "watermark"
+	RuleBlur      RuleType = "blur"
+	RuleFormat    RuleType = "format"
+)
+
+// Rule is one pipeline step. Only the fields relevant to Type are read; the
+// rest are ignored, mirroring how CI's rule string packs unrelated
+// parameters into one flat struct.
+type Rule struct {
+	Type RuleType `json:"type"`
+
+	// resize / crop: target (or crop-box) dimensions in pixels.
+	Width  int `json:"width,omitempty"`
+	Height int `json:"height,omitempty"`
+	X      int `json:"x,omitempty"` // crop/watermark origin
+	Y      int `json:"y,omitempty"`
+
+	// watermark: a text overlay.
+	Text     string `json:"text,omitempty"`
+	FontSize int    `json:"font_size,omitempty"`
+	Color    string `json:"color,omitempty"`
+
+	// blur: gaussian-style radius.
+	Radius float64 `json:"radius,omitempty"`
+
+	// format: output encoding. Validate accepts "jpeg" (default) or "webp",
+	// but the local backend only encodes jpeg: a "webp" rule fails when the
+	// pipeline runs.
+	Format string `json:"format,omitempty"`
+}
+
+// Validate rejects a Rule whose Type is unrecognized or whose required
+// fields are missing, so a bad pipeline fails fast instead of silently
+// no-op-ing inside the local backend.
+func (r Rule) Validate() error {
+	switch r.Type {
+	case RuleResize, RuleCrop:
+		if r.Width <= 0 || r.Height <= 0 {
+			return fmt.Errorf("%s rule requires positive width and height", r.Type)
+		}
+	case RuleWatermark:
+		if r.Text == "" {
+			return fmt.Errorf("watermark rule requires text")
+		}
+	case RuleBlur:
+		if r.Radius <= 0 {
+			return fmt.Errorf("blur rule requires a positive radius")
+		}
+	case RuleFormat:
+		if r.Format != "jpeg" && r.Format != "webp" {
+			return fmt.Errorf("format rule only supports jpeg or webp, got %q", r.Format)
+		}
+	default:
+		return fmt.Errorf("unknown pipeline rule type %q", r.Type)
+	}
+	return nil
+}
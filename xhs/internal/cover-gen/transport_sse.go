@@ -0,0 +1,166 @@
+package covergen
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// MCPRequest represents a generic MCP request
+type MCPRequest struct {
+	JSONRPC string      `json:"jsonrpc"`
+	Method  string      `json:"method"`
+	Params  interface{} `json:"params"`
+	ID      string      `json:"id"`
+}
+
+// MCPResponse represents the response from MCP server
+type MCPResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	Result  json.RawMessage `json:"result"`
+	Error   *MCPError       `json:"error,omitempty"`
+	ID      string          `json:"id"`
+}
+
+// MCPError represents an MCP error
+type MCPError struct {
+	Code    int         `json:"code"`
+	Message string      `json:"message"`
+	Data    interface{} `json:"data,omitempty"`
+}
+
+// progressFrame peeks an SSE event's shape: a "notifications/progress"
+// frame has a "method" and no "id", a JSON-RPC response has an "id" and no
+// "method".
+type progressFrame struct {
+	Method string `json:"method"`
+	Params struct {
+		ProgressToken string  `json:"progressToken"`
+		Progress      float64 `json:"progress"`
+		Total         float64 `json:"total"`
+	} `json:"params"`
+}
+
+// sseTransport is the MCP streamable-HTTP transport: every call is a POST
+// to the server's single endpoint, which may answer with a plain JSON body
+// or upgrade to text/event-stream to push "notifications/progress" frames
+// ahead of the final JSON-RPC response.
+type sseTransport struct {
+	httpClient *http.Client
+	url        string
+	nextID     int64
+}
+
+func newSSETransport(url string) *sseTransport {
+	return &sseTransport{
+		url: url,
+		httpClient: &http.Client{
+			Timeout: 120 * time.Second, // Image generation can take time
+		},
+	}
+}
+
+// call marshals method/params as JSON-RPC 2.0 and POSTs it, injecting a
+// _meta.progressToken into "tools/call" params so a long-running call
+// streams back progress frames instead of leaving the caller blind.
+func (t *sseTransport) call(ctx context.Context, method string, params interface{}) (json.RawMessage, error) {
+	id := atomic.AddInt64(&t.nextID, 1)
+	reqID := fmt.Sprintf("%s_%d", method, id)
+
+	if method == "tools/call" {
+		if m, ok := params.(map[string]interface{}); ok {
+			if _, has := m["_meta"]; !has {
+				m["_meta"] = map[string]interface{}{"progressToken": reqID}
+			}
+		}
+	}
+
+	reqBody, err := json.Marshal(MCPRequest{JSONRPC: "2.0", Method: method, Params: params, ID: reqID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal MCP request: %w", err)
+	}
+
+	mcpResp, err := t.post(ctx, reqBody)
+	if err != nil {
+		return nil, err
+	}
+	if mcpResp.Error != nil {
+		return nil, fmt.Errorf("MCP error %d: %s", mcpResp.Error.Code, mcpResp.Error.Message)
+	}
+	return mcpResp.Result, nil
+}
+
+func (t *sseTransport) close() error { return nil }
+
+// post sends reqBody to the server, asking it to upgrade to
+// text/event-stream so a long-running call can stream back progress frames.
+// Progress frames are logged as they arrive; the first JSON-RPC response
+// frame is decoded and returned. Servers that don't support streaming just
+// answer with a single JSON body, which is handled the same way.
+func (t *sseTransport) post(ctx context.Context, reqBody []byte) (*MCPResponse, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", t.url, bytes.NewBuffer(reqBody))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Accept", "application/json, text/event-stream")
+	// Propagate the trace context so the cover-gen MCP server's spans join
+	// this trace instead of starting a disconnected one.
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(httpReq.Header))
+
+	resp, err := t.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call MCP server: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("MCP server returned status %d", resp.StatusCode)
+	}
+
+	if !strings.HasPrefix(resp.Header.Get("Content-Type"), "text/event-stream") {
+		var mcpResp MCPResponse
+		if err := json.NewDecoder(resp.Body).Decode(&mcpResp); err != nil {
+			return nil, fmt.Errorf("failed to decode MCP response: %w", err)
+		}
+		return &mcpResp, nil
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		payload := strings.TrimPrefix(line, "data:")
+		if payload == line || strings.TrimSpace(payload) == "" {
+			continue // not a "data:" event line, or a blank frame separator
+		}
+		payload = strings.TrimSpace(payload)
+
+		var frame progressFrame
+		if err := json.Unmarshal([]byte(payload), &frame); err == nil && frame.Method == "notifications/progress" {
+			log.Printf("📊 cover-gen progress: %.0f/%.0f", frame.Params.Progress, frame.Params.Total)
+			continue
+		}
+
+		var mcpResp MCPResponse
+		if err := json.Unmarshal([]byte(payload), &mcpResp); err != nil {
+			return nil, fmt.Errorf("failed to decode MCP SSE frame: %w", err)
+		}
+		return &mcpResp, nil
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read MCP SSE stream: %w", err)
+	}
+	return nil, fmt.Errorf("MCP server closed the stream without a final response")
+}
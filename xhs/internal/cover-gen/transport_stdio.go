@@ -0,0 +1,98 @@
+package covergen
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"sync"
+	"sync/atomic"
+)
+
+// stdioTransport is MCP's stdio transport: the server runs as a subprocess
+// and JSON-RPC messages are exchanged as newline-delimited JSON over its
+// stdin/stdout. Calls are serialized with mu since a single stdin/stdout
+// pair can't interleave concurrent request/response pairs.
+type stdioTransport struct {
+	cmd    *exec.Cmd
+	stdin  io.WriteCloser
+	stdout *bufio.Reader
+
+	mu     sync.Mutex
+	nextID int64
+}
+
+func newStdioTransport(command string, args []string) (*stdioTransport, error) {
+	cmd := exec.Command(command, args...)
+	cmd.Stderr = os.Stderr
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open stdio MCP server's stdin: %w", err)
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open stdio MCP server's stdout: %w", err)
+	}
+
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start stdio MCP server %q: %w", command, err)
+	}
+
+	return &stdioTransport{
+		cmd:    cmd,
+		stdin:  stdin,
+		stdout: bufio.NewReader(stdout),
+	}, nil
+}
+
+func (t *stdioTransport) call(ctx context.Context, method string, params interface{}) (json.RawMessage, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	id := atomic.AddInt64(&t.nextID, 1)
+	reqID := fmt.Sprintf("%s_%d", method, id)
+	reqBody, err := json.Marshal(MCPRequest{
+		JSONRPC: "2.0",
+		Method:  method,
+		Params:  params,
+		ID:      reqID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal MCP request: %w", err)
+	}
+
+	if _, err := t.stdin.Write(append(reqBody, '\n')); err != nil {
+		return nil, fmt.Errorf("failed to write to stdio MCP server: %w", err)
+	}
+
+	// The stdio transport has no progress-frame channel: notifications (if
+	// any) arrive as their own newline-delimited lines before the response,
+	// so skip any frame that isn't a JSON-RPC response to this request.
+	for {
+		line, err := t.stdout.ReadBytes('\n')
+		if err != nil {
+			return nil, fmt.Errorf("failed to read from stdio MCP server: %w", err)
+		}
+
+		var resp MCPResponse
+		if err := json.Unmarshal(line, &resp); err != nil {
+			return nil, fmt.Errorf("failed to decode stdio MCP response: %w", err)
+		}
+		if resp.ID != reqID {
+			continue // a notification, not the response to this call
+		}
+		if resp.Error != nil {
+			return nil, fmt.Errorf("MCP error %d: %s", resp.Error.Code, resp.Error.Message)
+		}
+		return resp.Result, nil
+	}
+}
+
+func (t *stdioTransport) close() error {
+	t.stdin.Close()
+	return t.cmd.Wait()
+}
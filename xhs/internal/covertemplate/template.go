@@ -0,0 +1,175 @@
+package covertemplate
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"gopkg.in/yaml.v3"
+
+	"xiaohongshu-unified/internal/weather"
+)
+
+// TextLayer is one rendered text overlay: its content (a text/template
+// string evaluated against Data) plus the styling and positioning the
+// screenshot renderer expects as URL parameters.
+type TextLayer struct {
+	Text            string  `yaml:"text"`
+	FontFamily      string  `yaml:"font_family"`
+	FontSize        int     `yaml:"font_size"`
+	FontWeight      string  `yaml:"font_weight"`
+	Color           string  `yaml:"color"`
+	BackgroundColor string  `yaml:"background_color"`
+	TextShadow      string  `yaml:"text_shadow"`
+	Border          string  `yaml:"border"`
+	BorderRadius    int     `yaml:"border_radius"`
+	BorderWidth     int     `yaml:"border_width"`
+	BorderStyle     string  `yaml:"border_style"`
+	Padding         int     `yaml:"padding"`
+	ScaleX          float64 `yaml:"scale_x"`
+	ScaleY          float64 `yaml:"scale_y"`
+	SkewX           float64 `yaml:"skew_x"`
+	SkewY           float64 `yaml:"skew_y"`
+	Opacity         float64 `yaml:"opacity"`
+	OverlayColor    string  `yaml:"overlay_color"`
+	X               int     `yaml:"x"`
+	Y               int     `yaml:"y"`
+}
+
+// CoverTemplate is a reusable cover preset: a background image plus one or
+// more styled text layers whose Text fields may reference Data fields as
+// `{{.Weather.Description}}`, `{{.Date}}`, `{{.Visitors}}`, etc.
+type CoverTemplate struct {
+	Name        string      `yaml:"name"`
+	Description string      `yaml:"description"`
+	Image       string      `yaml:"image"`
+	Layers      []TextLayer `yaml:"layers"`
+}
+
+// Data is the set of values a template's text layers can reference.
+// Weather is nil when the cover is generated without live weather data.
+type Data struct {
+	Weather  *weather.WeatherInfo
+	Date     string
+	Visitors int
+}
+
+// Render evaluates the template's primary (first) text layer against data
+// and flattens the result into the args map expected by
+// handleGenerateXiaohongshuCover - the same key names the ad-hoc defaults
+// map used to hard-code. Callers merge this in as defaults, so explicit
+// caller-supplied args still win.
+func (t *CoverTemplate) Render(data Data) (map[string]interface{}, error) {
+	if len(t.Layers) == 0 {
+		return nil, fmt.Errorf("template %q has no text layers", t.Name)
+	}
+	layer := t.Layers[0]
+
+	text, err := renderPlaceholders(layer.Text, data)
+	if err != nil {
+		return nil, fmt.Errorf("render template %q: %w", t.Name, err)
+	}
+
+	args := map[string]interface{}{
+		"image":           t.Image,
+		"text":            text,
+		"fontFamily":      layer.FontFamily,
+		"fontSize":        layer.FontSize,
+		"fontWeight":      layer.FontWeight,
+		"color":           layer.Color,
+		"backgroundColor": layer.BackgroundColor,
+		"textShadow":      layer.TextShadow,
+		"border":          layer.Border,
+		"borderRadius":    layer.BorderRadius,
+		"borderWidth":     layer.BorderWidth,
+		"borderStyle":     layer.BorderStyle,
+		"padding":         layer.Padding,
+		"scaleX":          layer.ScaleX,
+		"scaleY":          layer.ScaleY,
+		"skewX":           layer.SkewX,
+		"skewY":           layer.SkewY,
+		"opacity":         layer.Opacity,
+		"overlayColor":    layer.OverlayColor,
+		"x":               layer.X,
+		"y":               layer.Y,
+	}
+	return args, nil
+}
+
+// renderPlaceholders evaluates a text/template string against data.
+func renderPlaceholders(tpl string, data Data) (string, error) {
+	parsed, err := template.New("layer").Parse(tpl)
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	if err := parsed.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// Registry holds every CoverTemplate loaded from a templates directory,
+// keyed by name.
+type Registry struct {
+	templates map[string]*CoverTemplate
+}
+
+// LoadDir walks dir for *.yaml/*.yml (and *.json) files and parses each one
+// into a CoverTemplate, keyed by its Name field. It's intentionally
+// non-recursive: one file per template, all in the same directory.
+func LoadDir(dir string) (*Registry, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read templates dir %q: %w", dir, err)
+	}
+
+	r := &Registry{templates: make(map[string]*CoverTemplate)}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		ext := strings.ToLower(filepath.Ext(entry.Name()))
+		if ext != ".yaml" && ext != ".yml" && ext != ".json" {
+			continue
+		}
+
+		path := filepath.Join(dir, entry.Name())
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read template %q: %w", path, err)
+		}
+
+		var tpl CoverTemplate
+		if err := yaml.Unmarshal(data, &tpl); err != nil {
+			return nil, fmt.Errorf("failed to parse template %q: %w", path, err)
+		}
+
+		if tpl.Name == "" {
+			tpl.Name = strings.TrimSuffix(entry.Name(), ext)
+		}
+		r.templates[tpl.Name] = &tpl
+	}
+
+	return r, nil
+}
+
+// Get looks up a template by name.
+func (r *Registry) Get(name string) (*CoverTemplate, bool) {
+	tpl, ok := r.templates[name]
+	return tpl, ok
+}
+
+// List returns every loaded template, for the list_cover_templates tool.
+func (r *Registry) List() []*CoverTemplate {
+	out := make([]*CoverTemplate, 0, len(r.templates))
+	for _, tpl := range r.templates {
+		out = append(out, tpl)
+	}
+	return out
+}
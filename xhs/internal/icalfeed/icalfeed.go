@@ -0,0 +1,186 @@
+// Package icalfeed publishes an RFC 5545 calendar of scheduled job runs,
+// upcoming lunar festivals/solar terms, and recent Weibo activity, so it
+// can be subscribed to from Apple Calendar / Google Calendar.
+package icalfeed
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	ics "github.com/arran4/golang-ical"
+
+	"xiaohongshu-unified/internal/lunar"
+	"xiaohongshu-unified/internal/scheduler"
+	"xiaohongshu-unified/internal/weibo"
+)
+
+// beijing is the timezone all calendar events are expressed in.
+var beijing = func() *time.Location {
+	loc, err := time.LoadLocation("Asia/Shanghai")
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}()
+
+// lunarLookaheadMonths is how far ahead solar terms and lunar festivals
+// are published.
+const lunarLookaheadMonths = 12
+
+// Feed builds and serves the combined iCal calendar.
+type Feed struct {
+	scheduler *scheduler.Scheduler
+	lunarSvc  *lunar.Service
+	weiboSvc  *weibo.Service
+}
+
+// NewFeed creates a new calendar feed over the given scheduler, lunar
+// service and weibo service. Any of them may be nil, in which case the
+// corresponding events are omitted.
+func NewFeed(sched *scheduler.Scheduler, lunarSvc *lunar.Service, weiboSvc *weibo.Service) *Feed {
+	return &Feed{
+		scheduler: sched,
+		lunarSvc:  lunarSvc,
+		weiboSvc:  weiboSvc,
+	}
+}
+
+// Handler returns an http.Handler serving GET /calendar.ics, honoring
+// If-None-Match against an ETag derived from the generated body.
+func (f *Feed) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		body, err := f.generate()
+		if err != nil {
+			log.Printf("❌ Failed to generate calendar feed: %v", err)
+			http.Error(w, "failed to generate calendar", http.StatusInternalServerError)
+			return
+		}
+
+		sum := sha256.Sum256(body)
+		etag := `"` + hex.EncodeToString(sum[:]) + `"`
+
+		w.Header().Set("ETag", etag)
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+		w.Write(body)
+	})
+}
+
+// generate renders the full VCALENDAR body.
+func (f *Feed) generate() ([]byte, error) {
+	cal := ics.NewCalendar()
+	cal.SetMethod(ics.MethodPublish)
+	cal.SetProductId("-//huanqiu-image-generator//calendar feed//CN")
+
+	f.addJobEvents(cal)
+	f.addLunarEvents(cal)
+	f.addWeiboEvents(cal)
+
+	return []byte(cal.Serialize()), nil
+}
+
+// addJobEvents adds one VEVENT per upcoming scheduler job fire time.
+func (f *Feed) addJobEvents(cal *ics.Calendar) {
+	if f.scheduler == nil {
+		return
+	}
+
+	now := time.Now()
+	for _, status := range f.scheduler.ListJobs() {
+		if status.NextRun.IsZero() {
+			continue
+		}
+
+		start := status.NextRun.In(beijing)
+		uid := fmt.Sprintf("job-%s-%d@huanqiu", status.Name, start.Unix())
+
+		event := cal.AddEvent(uid)
+		event.SetCreatedTime(now)
+		event.SetDtStampTime(now)
+		event.SetModifiedAt(now)
+		event.SetStartAt(start)
+		event.SetEndAt(start.Add(30 * time.Minute))
+		event.SetSummary(status.Name)
+		event.SetDescription(fmt.Sprintf("Scheduled run of job %q (last status: %s)", status.Name, status.LastStatus))
+	}
+}
+
+// addLunarEvents adds all-day VEVENTs for the next lunarLookaheadMonths of
+// solar terms and lunar festivals, computed offline.
+func (f *Feed) addLunarEvents(cal *ics.Calendar) {
+	if f.lunarSvc == nil {
+		return
+	}
+
+	now := time.Now()
+	until := now.AddDate(0, lunarLookaheadMonths, 0)
+
+	for day := now; day.Before(until); day = day.AddDate(0, 0, 1) {
+		info, err := f.lunarSvc.GetLunarInfoForDate(day)
+		if err != nil {
+			continue
+		}
+
+		if info.SolarTerm != "" {
+			f.addAllDayEvent(cal, fmt.Sprintf("term-%s-%s@huanqiu", info.SolarTerm, day.Format("20060102")), day, info.SolarTerm, "节气："+info.SolarTerm)
+		}
+		if info.Festival != "" {
+			f.addAllDayEvent(cal, fmt.Sprintf("festival-%s-%s@huanqiu", info.Festival, day.Format("20060102")), day, info.Festival, "节日："+info.Festival+"（"+info.LunarDate+"）")
+		}
+	}
+}
+
+// addAllDayEvent adds a single-day VEVENT with no specific start time.
+func (f *Feed) addAllDayEvent(cal *ics.Calendar, uid string, day time.Time, summary, description string) {
+	now := time.Now()
+	start := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, beijing)
+
+	event := cal.AddEvent(uid)
+	event.SetCreatedTime(now)
+	event.SetDtStampTime(now)
+	event.SetModifiedAt(now)
+	event.SetAllDayStartAt(start)
+	event.SetAllDayEndAt(start.AddDate(0, 0, 1))
+	event.SetSummary(summary)
+	event.SetDescription(description)
+}
+
+// addWeiboEvents adds informational VEVENTs for recently seen Weibo posts.
+func (f *Feed) addWeiboEvents(cal *ics.Calendar) {
+	if f.weiboSvc == nil {
+		return
+	}
+
+	posts, err := f.weiboSvc.GetLatestPosts(1)
+	if err != nil {
+		log.Printf("⚠️ Failed to fetch weibo posts for calendar feed: %v", err)
+		return
+	}
+
+	now := time.Now()
+	for _, post := range posts {
+		uid := fmt.Sprintf("weibo-%s@huanqiu", post.ID)
+
+		event := cal.AddEvent(uid)
+		event.SetCreatedTime(now)
+		event.SetDtStampTime(now)
+		event.SetModifiedAt(now)
+		event.SetStartAt(now)
+		event.SetEndAt(now.Add(time.Hour))
+		event.SetSummary(fmt.Sprintf("官微动态：%s", post.UserName))
+		event.SetDescription(post.Text)
+	}
+}
@@ -0,0 +1,206 @@
+package orchestrator
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+
+	"xiaohongshu-unified/internal/cover-gen"
+	"xiaohongshu-unified/internal/llm"
+	"xiaohongshu-unified/internal/lunar"
+	"xiaohongshu-unified/internal/traffic"
+	"xiaohongshu-unified/internal/visitor"
+	"xiaohongshu-unified/internal/weather"
+	"xiaohongshu-unified/internal/xhs"
+)
+
+// step marks how far a run has progressed, so Run/Resume know which steps
+// (and their LLM/image spend) can be skipped on retry.
+type step int
+
+const (
+	stepGathered step = iota
+	stepGenerated
+	stepCovered
+	stepCompleted
+)
+
+var checkpointsBucket = []byte("workflow_checkpoints")
+
+// WorkflowCheckpoint is the persisted state of one in-progress or completed
+// run of Orchestrator.Run, keyed by RunID. Saved after every step so a
+// crash between GenerateXiaohongshuCover succeeding and PostWithRetry
+// succeeding doesn't force the next tick to pay for the LLM call and the
+// cover render again.
+type WorkflowCheckpoint struct {
+	RunID   string `json:"run_id"`
+	Step    step   `json:"step"`
+	Weather *weather.WeatherInfo `json:"weather,omitempty"`
+	Lunar   *lunar.LunarInfo     `json:"lunar,omitempty"`
+	Traffic *traffic.TrafficInfo `json:"traffic,omitempty"`
+	Visitor *visitor.VisitorInfo `json:"visitor,omitempty"`
+	Weibo   string               `json:"weibo,omitempty"`
+
+	GeneratedContent *llm.GeneratedContent   `json:"generated_content,omitempty"`
+	ImageResponse    *covergen.ImageResponse `json:"image_response,omitempty"`
+	PostResponse     *xhs.PostResponse       `json:"post_response,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// checkpointStore persists WorkflowCheckpoints to a local BoltDB file, so
+// they survive a process crash between ticks.
+type checkpointStore struct {
+	db *bolt.DB
+}
+
+// openCheckpointStore opens (creating if needed) the BoltDB file at dbPath.
+func openCheckpointStore(dbPath string) (*checkpointStore, error) {
+	db, err := bolt.Open(dbPath, 0600, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open checkpoint store %q: %w", dbPath, err)
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(checkpointsBucket)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create checkpoint bucket: %w", err)
+	}
+
+	return &checkpointStore{db: db}, nil
+}
+
+// Close closes the underlying database.
+func (s *checkpointStore) Close() error {
+	return s.db.Close()
+}
+
+// get returns the checkpoint for runID, if one has been saved.
+func (s *checkpointStore) get(runID string) (*WorkflowCheckpoint, bool, error) {
+	var cp *WorkflowCheckpoint
+	err := s.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket(checkpointsBucket).Get([]byte(runID))
+		if data == nil {
+			return nil
+		}
+		cp = &WorkflowCheckpoint{}
+		return json.Unmarshal(data, cp)
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	return cp, cp != nil, nil
+}
+
+// put persists cp, overwriting any previous checkpoint for the same RunID.
+func (s *checkpointStore) put(cp *WorkflowCheckpoint) error {
+	data, err := json.Marshal(cp)
+	if err != nil {
+		return fmt.Errorf("failed to marshal checkpoint %q: %w", cp.RunID, err)
+	}
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(checkpointsBucket).Put([]byte(cp.RunID), data)
+	})
+}
+
+// latestUnfinished returns the most recently updated checkpoint whose Step
+// hasn't reached stepCompleted, or nil if every persisted run finished (or
+// none exist). Used by RunScheduled to resume a crashed run before starting
+// its next regular tick.
+func (s *checkpointStore) latestUnfinished() (*WorkflowCheckpoint, error) {
+	var latest *WorkflowCheckpoint
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(checkpointsBucket).ForEach(func(_, data []byte) error {
+			var cp WorkflowCheckpoint
+			if err := json.Unmarshal(data, &cp); err != nil {
+				return err
+			}
+			if cp.Step == stepCompleted {
+				return nil
+			}
+			if latest == nil || cp.UpdatedAt.After(latest.UpdatedAt) {
+				c := cp
+				latest = &c
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return latest, nil
+}
+
+// computeRunID derives a stable ID for the current hourly run slot plus the
+// inputs gathered for it, so a crash-and-retry within the same slot against
+// unchanged (cached) upstream data resumes the same checkpoint, while a
+// genuinely new slot or changed inputs start a fresh one. Each *Info's
+// Timestamp field is set to time.Now() at gather time, so it's stripped
+// before hashing - otherwise identical upstream data would still hash to a
+// different ID on every run and resume would never hit.
+func computeRunID(start time.Time, weatherInfo *weather.WeatherInfo, lunarInfo *lunar.LunarInfo, trafficInfo *traffic.TrafficInfo, visitorInfo *visitor.VisitorInfo, weibo string) string {
+	digest := struct {
+		Weather *weather.WeatherInfo
+		Lunar   *lunar.LunarInfo
+		Traffic *traffic.TrafficInfo
+		Visitor *visitor.VisitorInfo
+		Weibo   string
+	}{
+		Weather: stripWeatherTimestamp(weatherInfo),
+		Lunar:   stripLunarTimestamp(lunarInfo),
+		Traffic: stripTrafficTimestamp(trafficInfo),
+		Visitor: stripVisitorTimestamp(visitorInfo),
+		Weibo:   weibo,
+	}
+
+	data, err := json.Marshal(digest)
+	if err != nil {
+		data = []byte(fmt.Sprintf("%+v", digest))
+	}
+	sum := sha256.Sum256(data)
+	return fmt.Sprintf("%s-%s", start.Format("2006-01-02T15"), hex.EncodeToString(sum[:])[:8])
+}
+
+func stripWeatherTimestamp(w *weather.WeatherInfo) *weather.WeatherInfo {
+	if w == nil {
+		return nil
+	}
+	cp := *w
+	cp.Timestamp = time.Time{}
+	return &cp
+}
+
+func stripLunarTimestamp(l *lunar.LunarInfo) *lunar.LunarInfo {
+	if l == nil {
+		return nil
+	}
+	cp := *l
+	cp.Timestamp = time.Time{}
+	return &cp
+}
+
+func stripTrafficTimestamp(t *traffic.TrafficInfo) *traffic.TrafficInfo {
+	if t == nil {
+		return nil
+	}
+	cp := *t
+	cp.Timestamp = time.Time{}
+	return &cp
+}
+
+func stripVisitorTimestamp(v *visitor.VisitorInfo) *visitor.VisitorInfo {
+	if v == nil {
+		return nil
+	}
+	cp := *v
+	cp.Timestamp = time.Time{}
+	return &cp
+}
@@ -0,0 +1,86 @@
+package orchestrator
+
+import (
+	"testing"
+	"time"
+
+	"xiaohongshu-unified/internal/lunar"
+	"xiaohongshu-unified/internal/traffic"
+	"xiaohongshu-unified/internal/visitor"
+	"xiaohongshu-unified/internal/weather"
+)
+
+// TestComputeRunIDIgnoresGatherTimestamps guards against the bug where each
+// *Info's Timestamp (set to time.Now() at gather time) leaked into the
+// digest, so identical upstream data hashed to a different run ID on every
+// run and a crashed slot was never resumed.
+func TestComputeRunIDIgnoresGatherTimestamps(t *testing.T) {
+	start := time.Date(2026, 7, 29, 9, 0, 0, 0, time.UTC)
+
+	w1 := &weather.WeatherInfo{City: "北京", Temperature: 30, Timestamp: start}
+	w2 := &weather.WeatherInfo{City: "北京", Temperature: 30, Timestamp: start.Add(37 * time.Minute)}
+
+	l1 := &lunar.LunarInfo{LunarDate: "六月十六", Timestamp: start}
+	l2 := &lunar.LunarInfo{LunarDate: "六月十六", Timestamp: start.Add(2 * time.Hour)}
+
+	tr1 := &traffic.TrafficInfo{OverallStatus: "畅通", Timestamp: start}
+	tr2 := &traffic.TrafficInfo{OverallStatus: "畅通", Timestamp: start.Add(5 * time.Minute)}
+
+	v1 := &visitor.VisitorInfo{VisitorCount: 1200, Timestamp: start}
+	v2 := &visitor.VisitorInfo{VisitorCount: 1200, Timestamp: start.Add(90 * time.Second)}
+
+	id1 := computeRunID(start, w1, l1, tr1, v1, "weibo hot")
+	id2 := computeRunID(start, w2, l2, tr2, v2, "weibo hot")
+
+	if id1 != id2 {
+		t.Errorf("computeRunID differed for identical inputs with only Timestamp changed: %q vs %q", id1, id2)
+	}
+}
+
+// TestComputeRunIDDiffersOnSemanticChange makes sure stripping the
+// timestamps didn't accidentally make computeRunID ignore real input
+// changes too.
+func TestComputeRunIDDiffersOnSemanticChange(t *testing.T) {
+	start := time.Date(2026, 7, 29, 9, 0, 0, 0, time.UTC)
+
+	w1 := &weather.WeatherInfo{City: "北京", Temperature: 30, Timestamp: start}
+	w2 := &weather.WeatherInfo{City: "北京", Temperature: 31, Timestamp: start}
+
+	id1 := computeRunID(start, w1, nil, nil, nil, "weibo hot")
+	id2 := computeRunID(start, w2, nil, nil, nil, "weibo hot")
+
+	if id1 == id2 {
+		t.Errorf("computeRunID was the same despite a real change in weather input: %q", id1)
+	}
+}
+
+// TestCheckpointStoreLatestUnfinishedSkipsCompleted exercises the step
+// boundary the resume path relies on: latestUnfinished must return a run
+// stuck at an intermediate step, and must not return one that already
+// reached stepCompleted.
+func TestCheckpointStoreLatestUnfinishedSkipsCompleted(t *testing.T) {
+	dir := t.TempDir()
+	store, err := openCheckpointStore(dir + "/checkpoints.db")
+	if err != nil {
+		t.Fatalf("openCheckpointStore: %v", err)
+	}
+	defer store.Close()
+
+	done := &WorkflowCheckpoint{RunID: "2026-07-29T09-done", Step: stepCompleted, UpdatedAt: time.Now()}
+	if err := store.put(done); err != nil {
+		t.Fatalf("put(done): %v", err)
+	}
+
+	stuck := &WorkflowCheckpoint{RunID: "2026-07-29T10-stuck", Step: stepGenerated, UpdatedAt: time.Now().Add(time.Minute)}
+	if err := store.put(stuck); err != nil {
+		t.Fatalf("put(stuck): %v", err)
+	}
+
+	got, err := store.latestUnfinished()
+	if err != nil {
+		t.Fatalf("latestUnfinished: %v", err)
+	}
+	if got == nil || got.RunID != stuck.RunID {
+		t.Errorf("latestUnfinished() = %+v, want RunID %q", got, stuck.RunID)
+	}
+}
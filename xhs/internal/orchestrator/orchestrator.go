@@ -1,10 +1,19 @@
 package orchestrator
 
 import (
+	"context"
 	"fmt"
 	"log"
+	"os"
+	"path/filepath"
 	"time"
 
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"xiaohongshu-unified/internal/cache"
 	"xiaohongshu-unified/internal/config"
 	"xiaohongshu-unified/internal/llm"
 	"xiaohongshu-unified/internal/lunar"
@@ -16,6 +25,18 @@ import (
 	"xiaohongshu-unified/internal/xhs"
 )
 
+// tracer emits spans for every step of the workflow so a slow nightly run
+// can be diagnosed by which sub-service (weather, LLM, cover-gen MCP, xhs
+// MCP) actually took the time, instead of just the log timestamps.
+var tracer = otel.Tracer("xiaohongshu-unified/orchestrator")
+
+// recordFailure adds a span event for a step that failed but didn't abort
+// the workflow (the ⚠️ log lines below), so partial failures still show up
+// on the trace even though Run keeps going.
+func recordFailure(span trace.Span, step string, err error) {
+	span.AddEvent(step+" failed", trace.WithAttributes(attribute.String("error", err.Error())))
+}
+
 // Orchestrator coordinates all services to generate and post content
 type Orchestrator struct {
 	cfg           *config.Config
@@ -27,11 +48,13 @@ type Orchestrator struct {
 	llmSvc        *llm.Service
 	coverMCPClient     *covergen.Client
 	xhsClient     *xhs.Client
+	prefetch      *cache.PrefetchScheduler
+	checkpoints   *checkpointStore // nil if the checkpoint store failed to open; Run then skips persistence
 }
 
 // New creates a new orchestrator
 func New(cfg *config.Config) *Orchestrator {
-	return &Orchestrator{
+	o := &Orchestrator{
 		cfg:        cfg,
 		weatherSvc: weather.NewService(cfg),
 		lunarSvc:   lunar.NewService(),
@@ -42,10 +65,62 @@ func New(cfg *config.Config) *Orchestrator {
 		coverMCPClient:  covergen.NewClient(cfg),
 		xhsClient:  xhs.NewClient(cfg),
 	}
+
+	if err := os.MkdirAll(cfg.Settings.StateDir, 0755); err != nil {
+		log.Printf("⚠️ checkpoint: failed to create state dir %q, checkpointing disabled: %v", cfg.Settings.StateDir, err)
+	} else if checkpoints, err := openCheckpointStore(filepath.Join(cfg.Settings.StateDir, "workflow_checkpoints.db")); err != nil {
+		log.Printf("⚠️ checkpoint: failed to open checkpoint store, checkpointing disabled: %v", err)
+	} else {
+		o.checkpoints = checkpoints
+	}
+
+	o.startPrefetch()
+	return o
+}
+
+// saveCheckpoint persists cp, logging (but not failing the workflow) if the
+// checkpoint store isn't available or the write fails.
+func (o *Orchestrator) saveCheckpoint(cp *WorkflowCheckpoint) {
+	if o.checkpoints == nil {
+		return
+	}
+	cp.UpdatedAt = time.Now()
+	if err := o.checkpoints.put(cp); err != nil {
+		log.Printf("⚠️ checkpoint: failed to save run %s: %v", cp.RunID, err)
+	}
+}
+
+// startPrefetch warms the weather/traffic/visitor caches a configurable
+// number of minutes before each Settings.PostInterval boundary, so Run
+// doesn't block on a cold upstream right when the scheduled job fires.
+// It's best-effort: a misconfigured PostInterval just disables warming.
+func (o *Orchestrator) startPrefetch() {
+	interval, err := time.ParseDuration(o.cfg.Settings.PostInterval)
+	if err != nil {
+		log.Printf("⚠️ prefetch: invalid post interval %q, cache warming disabled: %v", o.cfg.Settings.PostInterval, err)
+		return
+	}
+
+	lead := time.Duration(o.cfg.Settings.PrefetchLeadMinutes) * time.Minute
+	if lead <= 0 {
+		lead = 5 * time.Minute
+	}
+
+	prefetch := cache.NewPrefetchScheduler(interval, lead)
+	prefetch.Register(o.weatherSvc.PrefetchSource())
+	prefetch.Register(o.trafficSvc.PrefetchSource())
+	prefetch.Register(o.visitorSvc.PrefetchSource())
+
+	if err := prefetch.Start(); err != nil {
+		log.Printf("⚠️ prefetch: failed to start cache warmer: %v", err)
+		return
+	}
+	o.prefetch = prefetch
 }
 
 // WorkflowResult represents the result of the complete workflow
 type WorkflowResult struct {
+	RunID           string               `json:"run_id,omitempty"`
 	WeatherInfo     *weather.WeatherInfo `json:"weather_info"`
 	LunarInfo       *lunar.LunarInfo     `json:"lunar_info"`
 	TrafficInfo     *traffic.TrafficInfo `json:"traffic_info"`
@@ -60,137 +135,328 @@ type WorkflowResult struct {
 	Error           string               `json:"error,omitempty"`
 }
 
-// Run executes the complete workflow
-func (o *Orchestrator) Run() error {
+// Run executes the complete workflow, checkpointing progress after every
+// step so a crash doesn't re-pay for LLM/image generation already done for
+// this run slot (see the resumption logic in run).
+func (o *Orchestrator) Run(ctx context.Context) error {
+	_, err := o.run(ctx, "", false)
+	return err
+}
+
+// Resume re-enters a specific checkpointed run by ID, picking up from
+// whichever step it last completed instead of gathering fresh inputs. It's
+// the entry point for the --resume CLI flag and for RunScheduled's
+// automatic resume of a crashed run.
+func (o *Orchestrator) Resume(runID string) error {
+	_, err := o.Approve(runID)
+	return err
+}
+
+// Preview runs steps 1-4 (gather inputs, generate content, render the
+// cover) and stops, leaving the checkpoint at stepCovered instead of
+// posting. The returned WorkflowResult.RunID can be handed to Approve (or
+// the admin API's POST /runs/:id/approve) to post it, or to GetCheckpoint
+// to inspect it, once a human has reviewed the would-be title and cover.
+func (o *Orchestrator) Preview(ctx context.Context) (*WorkflowResult, error) {
+	return o.run(ctx, "", true)
+}
+
+// Approve posts a previously previewed (or otherwise unfinished) run by
+// RunID, via the same step-skipping logic Resume uses - it's Resume's
+// sibling for callers (like the admin API) that want the WorkflowResult
+// back instead of just an error.
+func (o *Orchestrator) Approve(runID string) (*WorkflowResult, error) {
+	if o.checkpoints == nil {
+		return nil, fmt.Errorf("checkpointing is disabled, nothing to resume")
+	}
+	return o.run(context.Background(), runID, false)
+}
+
+// GetCheckpoint returns the persisted checkpoint for runID, for the admin
+// API's GET /runs/:id.
+func (o *Orchestrator) GetCheckpoint(runID string) (*WorkflowCheckpoint, error) {
+	if o.checkpoints == nil {
+		return nil, fmt.Errorf("checkpointing is disabled")
+	}
+	cp, found, err := o.checkpoints.get(runID)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, fmt.Errorf("no checkpoint found for run %q", runID)
+	}
+	return cp, nil
+}
+
+// run executes the workflow. If resumeRunID is empty, it gathers fresh
+// inputs and derives a run ID from them (see computeRunID), reusing any
+// unfinished checkpoint already on record for that exact slot+inputs
+// combination. If resumeRunID is non-empty, it loads that checkpoint
+// instead of gathering anything, and continues from its recorded step.
+// When preview is true, it stops after the cover image step and never
+// posts, regardless of how far the checkpoint has progressed.
+func (o *Orchestrator) run(ctx context.Context, resumeRunID string, preview bool) (*WorkflowResult, error) {
+	ctx, span := tracer.Start(ctx, "orchestrator.Run")
+	defer span.End()
+
 	start := time.Now()
 	result := &WorkflowResult{
 		Timestamp: start,
 	}
 
+	var cp *WorkflowCheckpoint
+	if resumeRunID != "" {
+		loaded, found, err := o.checkpoints.get(resumeRunID)
+		if err != nil || !found {
+			return nil, fmt.Errorf("no checkpoint found for run %q", resumeRunID)
+		}
+		cp = loaded
+		log.Printf("↻ Resuming run %s from step %d", cp.RunID, cp.Step)
+	}
+
 	log.Println("🚀 Starting Xiaohongshu content generation workflow...")
 
 	// Step 1: Test connections
-	if err := o.testConnections(); err != nil {
+	if err := o.testConnections(ctx); err != nil {
 		result.Error = fmt.Sprintf("Connection test failed: %v", err)
 		log.Printf("❌ %s", result.Error)
-		return fmt.Errorf(result.Error)
+		span.SetStatus(codes.Error, result.Error)
+		return nil, fmt.Errorf(result.Error)
 	}
 	log.Println("✅ All service connections tested successfully")
 
-	// Step 2: Gather information
-	log.Println("📊 Gathering information...")
-
-	// Get weather information
-	weatherInfo, err := o.weatherSvc.GetWeatherInfo()
-	if err != nil {
-		log.Printf("⚠️ Failed to get weather info: %v", err)
-		// Continue without weather info
+	// Step 2: Gather information (skipped entirely when resuming a
+	// checkpoint - its stored inputs are reused as-is).
+	var weatherInfo *weather.WeatherInfo
+	var lunarInfo *lunar.LunarInfo
+	var trafficInfo *traffic.TrafficInfo
+	var visitorInfo *visitor.VisitorInfo
+	var weiboSummaryContent string
+
+	if cp != nil {
+		weatherInfo, lunarInfo, trafficInfo, visitorInfo, weiboSummaryContent =
+			cp.Weather, cp.Lunar, cp.Traffic, cp.Visitor, cp.Weibo
 	} else {
-		result.WeatherInfo = weatherInfo
-		log.Printf("🌤️ Weather: %s", weatherInfo.GetFormattedWeather())
-	}
+		log.Println("📊 Gathering information...")
+
+		// Get weather information
+		weatherCtx, weatherSpan := tracer.Start(ctx, "weather.GetWeatherInfo")
+		var err error
+		weatherInfo, err = o.weatherSvc.GetWeatherInfo(weatherCtx)
+		if err != nil {
+			log.Printf("⚠️ Failed to get weather info: %v", err)
+			recordFailure(weatherSpan, "weather.GetWeatherInfo", err)
+			// Continue without weather info
+		} else {
+			weatherSpan.SetAttributes(attribute.String("weather.location", o.cfg.WeatherAPI.City))
+			log.Printf("🌤️ Weather: %s", weatherInfo.GetFormattedWeather())
+		}
+		weatherSpan.End()
+
+		// Get lunar information
+		lunarInfo, err = o.lunarSvc.GetLunarInfo()
+		if err != nil {
+			log.Printf("⚠️ Failed to get lunar info: %v", err)
+			recordFailure(span, "lunar.GetLunarInfo", err)
+			// Continue without lunar info
+		} else {
+			log.Printf("📅 Lunar: %s", lunarInfo.GetFormattedLunar())
+		}
 
-	// Get lunar information
-	lunarInfo, err := o.lunarSvc.GetLunarInfo()
-	if err != nil {
-		log.Printf("⚠️ Failed to get lunar info: %v", err)
-		// Continue without lunar info
-	} else {
-		result.LunarInfo = lunarInfo
-		log.Printf("📅 Lunar: %s", lunarInfo.GetFormattedLunar())
-	}
+		// Get traffic information
+		trafficInfo, err = o.trafficSvc.GetTrafficInfo()
+		if err != nil {
+			log.Printf("⚠️ Failed to get traffic info: %v", err)
+			recordFailure(span, "traffic.GetTrafficInfo", err)
+			// Continue without traffic info
+		} else {
+			log.Printf("🚗 Traffic: %s overall status", trafficInfo.OverallStatus)
+		}
 
-	// Get traffic information
-	trafficInfo, err := o.trafficSvc.GetTrafficInfo()
-	if err != nil {
-		log.Printf("⚠️ Failed to get traffic info: %v", err)
-		// Continue without traffic info
-	} else {
-		result.TrafficInfo = trafficInfo
-		log.Printf("🚗 Traffic: %s overall status", trafficInfo.OverallStatus)
-	}
+		// Get visitor information
+		visitorInfo, err = o.visitorSvc.GetVisitorInfo()
+		if err != nil {
+			log.Printf("⚠️ Failed to get visitor info: %v", err)
+			recordFailure(span, "visitor.GetVisitorInfo", err)
+			// Continue without visitor info
+		} else {
+			log.Printf("👥 Visitor: %s", visitorInfo.GetFormattedVisitor())
+		}
 
-	// Get visitor information
-	visitorInfo, err := o.visitorSvc.GetVisitorInfo()
-	if err != nil {
-		log.Printf("⚠️ Failed to get visitor info: %v", err)
-		// Continue without visitor info
-	} else {
-		result.VisitorInfo = visitorInfo
-		log.Printf("👥 Visitor: %s", visitorInfo.GetFormattedVisitor())
-	}
+		// Get weibo content for summary
+		weiboSummaryContent, err = o.weiboSvc.GetTop2PostsForSummary()
+		if err != nil {
+			log.Printf("Warning: Failed to get weibo summary content: %v", err)
+			weiboSummaryContent = "" // Continue without weibo content
+		}
+		if weiboSummaryContent != "" {
+			log.Printf("📱 Weibo: Got recent content")
+		}
 
-	// Get weibo content for summary
-	weiboSummaryContent, err := o.weiboSvc.GetTop2PostsForSummary()
-	if err != nil {
-		log.Printf("Warning: Failed to get weibo summary content: %v", err)
-		weiboSummaryContent = "" // Continue without weibo content
+		runID := computeRunID(start, weatherInfo, lunarInfo, trafficInfo, visitorInfo, weiboSummaryContent)
+		if o.checkpoints != nil {
+			if existing, found, err := o.checkpoints.get(runID); err != nil {
+				log.Printf("⚠️ checkpoint: failed to look up run %s: %v", runID, err)
+			} else if found && existing.Step != stepCompleted {
+				log.Printf("↻ Found unfinished checkpoint %s from this run slot, resuming from step %d", runID, existing.Step)
+				cp = existing
+			}
+		}
+		if cp == nil {
+			cp = &WorkflowCheckpoint{
+				RunID:     runID,
+				Step:      stepGathered,
+				Weather:   weatherInfo,
+				Lunar:     lunarInfo,
+				Traffic:   trafficInfo,
+				Visitor:   visitorInfo,
+				Weibo:     weiboSummaryContent,
+				CreatedAt: start,
+			}
+		}
+		o.saveCheckpoint(cp)
 	}
+
+	result.RunID = cp.RunID
+	result.WeatherInfo = weatherInfo
+	result.LunarInfo = lunarInfo
+	result.TrafficInfo = trafficInfo
+	result.VisitorInfo = visitorInfo
 	result.WeiboContent = weiboSummaryContent
-	if weiboSummaryContent != "" {
-		log.Printf("📱 Weibo: Got recent content")
-	}
 
-	// Step 3: Generate content using LLM
-	log.Println("🤖 Generating content with DeepSeek LLM...")
-	contentReq := &llm.ContentRequest{
-		Weather: weatherInfo,
-		Lunar:   lunarInfo,
-		// Traffic: trafficInfo, // Omitted per user request
-		Visitor: visitorInfo,
-		Weibo:   weiboSummaryContent,
-		Theme:   "daily life sharing", // You can make this configurable
-	}
+	// Step 3: Generate content using LLM (skipped if this checkpoint
+	// already has a GeneratedContent from a prior attempt)
+	var generatedContent *llm.GeneratedContent
+	if cp.Step == stepGathered {
+		log.Println("🤖 Generating content with DeepSeek LLM...")
+		contentReq := &llm.ContentRequest{
+			Weather: weatherInfo,
+			Lunar:   lunarInfo,
+			// Traffic: trafficInfo, // Omitted per user request
+			Visitor: visitorInfo,
+			Weibo:   weiboSummaryContent,
+			Theme:   "daily life sharing", // You can make this configurable
+		}
 
-	generatedContent, err := o.llmSvc.GenerateContent(contentReq)
-	if err != nil {
-		result.Error = fmt.Sprintf("Content generation failed: %v", err)
-		log.Printf("❌ %s", result.Error)
-		return fmt.Errorf(result.Error)
+		llmCtx, llmSpan := tracer.Start(ctx, "llm.GenerateContent")
+		var err error
+		generatedContent, err = o.llmSvc.GenerateContent(llmCtx, contentReq)
+		if err != nil {
+			result.Error = fmt.Sprintf("Content generation failed: %v", err)
+			log.Printf("❌ %s", result.Error)
+			llmSpan.SetStatus(codes.Error, result.Error)
+			llmSpan.End()
+			span.SetStatus(codes.Error, result.Error)
+			return nil, fmt.Errorf(result.Error)
+		}
+		llmSpan.SetAttributes(attribute.Int("llm.prompt_tokens", generatedContent.PromptTokens))
+		llmSpan.End()
+		log.Printf("✅ Content generated: %s", generatedContent.Title)
+
+		cp.GeneratedContent = generatedContent
+		cp.Step = stepGenerated
+		o.saveCheckpoint(cp)
+	} else {
+		generatedContent = cp.GeneratedContent
+		log.Printf("↻ Reusing checkpointed content: %s", generatedContent.Title)
 	}
 	result.GeneratedContent = generatedContent
-	log.Printf("✅ Content generated: %s", generatedContent.Title)
-
-	// Step 4: Generate cover image
-	log.Println("🎨 Generating cover image...")
-	// Use a default image prompt and the cover_text from LLM response
-	defaultImagePrompt := "cozy daily life scene, warm lighting, lifestyle photography, Beijing Universal Studios theme park"
-	imageResp, err := o.coverMCPClient.GenerateXiaohongshuCover(
-		defaultImagePrompt,
-		generatedContent.CoverText,
-	)
-	if err != nil {
-		result.Error = fmt.Sprintf("Image generation failed: %v", err)
-		log.Printf("❌ %s", result.Error)
-		return fmt.Errorf(result.Error)
+
+	// Step 4: Generate cover image (skipped if this checkpoint already has
+	// an ImageResponse from a prior attempt)
+	var imageResp *covergen.ImageResponse
+	if cp.Step == stepGenerated {
+		log.Println("🎨 Generating cover image...")
+		// Use a default image prompt and the cover_text from LLM response
+		defaultImagePrompt := "cozy daily life scene, warm lighting, lifestyle photography, Beijing Universal Studios theme park"
+
+		// Render the last two weeks of visitor history as an overlay chart; a
+		// nil/failed render just means the cover ships without it.
+		trendPNG, err := o.visitorSvc.RenderTrendPNG(14, 600, 200)
+		if err != nil {
+			log.Printf("⚠️ Failed to render visitor trend chart: %v", err)
+			recordFailure(span, "visitor.RenderTrendPNG", err)
+			trendPNG = nil
+		}
+
+		coverCtx, coverSpan := tracer.Start(ctx, "covergen.GenerateXiaohongshuCover")
+		imageResp, err = o.coverMCPClient.GenerateXiaohongshuCover(
+			coverCtx,
+			defaultImagePrompt,
+			generatedContent.CoverText,
+			trendPNG,
+		)
+		if err != nil {
+			result.Error = fmt.Sprintf("Image generation failed: %v", err)
+			log.Printf("❌ %s", result.Error)
+			coverSpan.SetStatus(codes.Error, result.Error)
+			coverSpan.End()
+			span.SetStatus(codes.Error, result.Error)
+			return nil, fmt.Errorf(result.Error)
+		}
+		coverSpan.SetAttributes(attribute.String("cover.image_url", imageResp.ImageURL))
+		coverSpan.End()
+		log.Printf("✅ Cover image generated: %s", imageResp.ImageURL)
+
+		cp.ImageResponse = imageResp
+		cp.Step = stepCovered
+		o.saveCheckpoint(cp)
+	} else {
+		imageResp = cp.ImageResponse
+		log.Printf("↻ Reusing checkpointed cover image: %s", imageResp.ImageURL)
 	}
 	result.ImageResponse = imageResp
-	log.Printf("✅ Cover image generated: %s", imageResp.ImageURL)
 
-	// Step 5: Post to Xiaohongshu
-	log.Println("📱 Posting to Xiaohongshu...")
-	postReq := &xhs.PostRequest{
-		Title:   generatedContent.Title,
-		Content: generatedContent.GetFormattedContent(),
-		Images:  []string{imageResp.ImageURL},
+	// Step 5: Post to Xiaohongshu (skipped if this checkpoint already
+	// posted successfully, or if this is only a preview - the checkpoint
+	// is left at stepCovered so a later Approve can pick it up).
+	var postResp *xhs.PostResponse
+	if preview && cp.Step == stepCovered {
+		log.Printf("👀 Preview mode - stopping before posting, run ID: %s", cp.RunID)
+		result.ExecutionTime = time.Since(start)
+		result.Success = true
+		return result, nil
 	}
 
-	// Validate post request
-	if err := o.xhsClient.ValidatePostRequest(postReq); err != nil {
-		result.Error = fmt.Sprintf("Post validation failed: %v", err)
-		log.Printf("❌ %s", result.Error)
-		return fmt.Errorf(result.Error)
-	}
+	if cp.Step == stepCovered {
+		log.Println("📱 Posting to Xiaohongshu...")
+		postReq := &xhs.PostRequest{
+			Title:   generatedContent.Title,
+			Content: generatedContent.GetFormattedContent(),
+			Images:  []string{imageResp.ImageURL},
+		}
 
-	// Post with retry
-	postResp, err := o.xhsClient.PostWithRetry(postReq, 3)
-	if err != nil {
-		result.Error = fmt.Sprintf("Posting failed: %v", err)
-		log.Printf("❌ %s", result.Error)
-		return fmt.Errorf(result.Error)
+		// Validate post request
+		if err := o.xhsClient.ValidatePostRequest(postReq); err != nil {
+			result.Error = fmt.Sprintf("Post validation failed: %v", err)
+			log.Printf("❌ %s", result.Error)
+			return nil, fmt.Errorf(result.Error)
+		}
+
+		// Post with retry
+		postCtx, postSpan := tracer.Start(ctx, "xhs.PostWithRetry")
+		var err error
+		postResp, err = o.xhsClient.PostWithRetry(postCtx, postReq, 3)
+		if err != nil {
+			result.Error = fmt.Sprintf("Posting failed: %v", err)
+			log.Printf("❌ %s", result.Error)
+			postSpan.SetStatus(codes.Error, result.Error)
+			postSpan.End()
+			span.SetStatus(codes.Error, result.Error)
+			return nil, fmt.Errorf(result.Error)
+		}
+		postSpan.SetAttributes(attribute.String("xhs.post_id", postResp.PostID))
+		postSpan.End()
+		log.Printf("✅ Posted successfully: %s", postResp.URL)
+
+		cp.PostResponse = postResp
+		cp.Step = stepCompleted
+		o.saveCheckpoint(cp)
+	} else {
+		postResp = cp.PostResponse
+		log.Printf("↻ Run %s was already posted as %s, nothing left to do", cp.RunID, postResp.PostID)
 	}
 	result.PostResponse = postResp
-	log.Printf("✅ Posted successfully: %s", postResp.URL)
 
 	// Step 6: Complete workflow
 	result.ExecutionTime = time.Since(start)
@@ -200,51 +466,77 @@ func (o *Orchestrator) Run() error {
 	log.Printf("📝 Post ID: %s", postResp.PostID)
 	log.Printf("🔗 Post URL: %s", postResp.URL)
 
-	return nil
+	return result, nil
 }
 
 // testConnections tests all external service connections
-func (o *Orchestrator) testConnections() error {
+func (o *Orchestrator) testConnections(ctx context.Context) error {
+	ctx, span := tracer.Start(ctx, "orchestrator.testConnections")
+	defer span.End()
+
 	log.Println("🔍 Testing service connections...")
 
 	// Test MCP server connection
-	if err := o.coverMCPClient.TestConnection(); err != nil {
+	if err := o.coverMCPClient.TestConnection(ctx); err != nil {
+		span.SetStatus(codes.Error, err.Error())
 		return fmt.Errorf("MCP server connection failed: %w", err)
 	}
 	log.Println("✅ MCP server connection OK")
 
 	// Test Xiaohongshu MCP server connection
-	if err := o.xhsClient.TestConnection(); err != nil {
+	if err := o.xhsClient.TestConnection(ctx); err != nil {
+		span.SetStatus(codes.Error, err.Error())
 		return fmt.Errorf("Xiaohongshu MCP server connection failed: %w", err)
 	}
 	log.Println("✅ Xiaohongshu MCP server connection OK")
 
+	log.Printf("📦 Cache hit rates — weather: %.0f%%, traffic: %.0f%%, visitor: %.0f%%",
+		o.weatherSvc.CacheHitRate()*100, o.trafficSvc.CacheHitRate()*100, o.visitorSvc.CacheHitRate()*100)
+
 	return nil
 }
 
-// RunScheduled runs the workflow on a schedule
-func (o *Orchestrator) RunScheduled() error {
+// RunScheduled runs the workflow on a schedule. When previewOnly is true,
+// every tick stops after the cover-image step instead of posting, leaving a
+// checkpoint that a human approves via the admin API's
+// POST /runs/:id/approve (or the --resume CLI flag) before it goes out.
+func (o *Orchestrator) RunScheduled(previewOnly bool) error {
 	interval, err := time.ParseDuration(o.cfg.Settings.PostInterval)
 	if err != nil {
 		return fmt.Errorf("invalid post interval: %w", err)
 	}
 
-	log.Printf("📅 Starting scheduled workflow with interval: %v", interval)
+	tick := o.Run
+	if previewOnly {
+		tick = o.previewAndLogForApproval
+	}
+
+	log.Printf("📅 Starting scheduled workflow with interval: %v (preview-only: %v)", interval, previewOnly)
 
 	ticker := time.NewTicker(interval)
 	defer ticker.Stop()
 
 	// Run once immediately
-	if err := o.Run(); err != nil {
+	if !previewOnly {
+		o.resumeUnfinished()
+	}
+	if err := tick(context.Background()); err != nil {
 		log.Printf("❌ Initial run failed: %v", err)
 	}
 
-	// Then run on schedule
+	// Then run on schedule, resuming any run left unfinished by the previous
+	// tick before starting a new one, so a post failure on tick N doesn't
+	// get re-gathered and re-paid for on tick N+1. In preview-only mode
+	// nothing is resumed here, since resuming a previewed run would post it
+	// without approval.
 	for {
 		select {
 		case <-ticker.C:
 			log.Println("⏰ Scheduled run starting...")
-			if err := o.Run(); err != nil {
+			if !previewOnly {
+				o.resumeUnfinished()
+			}
+			if err := tick(context.Background()); err != nil {
 				log.Printf("❌ Scheduled run failed: %v", err)
 				// Continue with next scheduled run
 			}
@@ -252,31 +544,83 @@ func (o *Orchestrator) RunScheduled() error {
 	}
 }
 
+// previewAndLogForApproval runs Preview and logs where to approve it,
+// matching Run's error-returning signature so RunScheduled can treat it the
+// same as a normal tick.
+func (o *Orchestrator) previewAndLogForApproval(ctx context.Context) error {
+	result, err := o.Preview(ctx)
+	if err != nil {
+		return err
+	}
+	log.Printf("⏳ Run %s awaits approval - POST /runs/%s/approve or --resume=%s", result.RunID, result.RunID, result.RunID)
+	return nil
+}
+
 // RunOnce runs the workflow once and exits
 func (o *Orchestrator) RunOnce() error {
-	return o.Run()
+	return o.Run(context.Background())
+}
+
+// resumeUnfinished looks for the most recent checkpoint left unfinished by
+// a crashed run and resumes it, so a scheduled loop doesn't silently leave
+// already-generated content/images stranded on disk.
+func (o *Orchestrator) resumeUnfinished() {
+	if o.checkpoints == nil {
+		return
+	}
+
+	cp, err := o.checkpoints.latestUnfinished()
+	if err != nil {
+		log.Printf("⚠️ checkpoint: failed to check for unfinished runs: %v", err)
+		return
+	}
+	if cp == nil {
+		return
+	}
+
+	log.Printf("↻ Resuming unfinished run %s before starting the scheduled loop", cp.RunID)
+	if err := o.Resume(cp.RunID); err != nil {
+		log.Printf("⚠️ Failed to resume run %s: %v", cp.RunID, err)
+	}
+}
+
+// WeiboSummary returns a short summary of recent official Weibo posts, for
+// use in push notifications.
+func (o *Orchestrator) WeiboSummary() (string, error) {
+	return o.weiboSvc.GetTop2PostsForSummary()
+}
+
+// LunarLine returns today's formatted lunar/solar-term line, for use in
+// push notifications and the "morning brief" job.
+func (o *Orchestrator) LunarLine() (string, error) {
+	lunarInfo, err := o.lunarSvc.GetLunarInfo()
+	if err != nil {
+		return "", fmt.Errorf("failed to get lunar info: %w", err)
+	}
+	return lunarInfo.GetFormattedLunar(), nil
 }
 
 // GetServiceStatus returns the status of all services
 func (o *Orchestrator) GetServiceStatus() map[string]string {
+	ctx := context.Background()
 	status := make(map[string]string)
 
 	// Test MCP server
-	if err := o.coverMCPClient.TestConnection(); err != nil {
+	if err := o.coverMCPClient.TestConnection(ctx); err != nil {
 		status["mcp_server"] = fmt.Sprintf("❌ Error: %v", err)
 	} else {
 		status["mcp_server"] = "✅ OK"
 	}
 
 	// Test Xiaohongshu MCP server
-	if err := o.xhsClient.TestConnection(); err != nil {
+	if err := o.xhsClient.TestConnection(ctx); err != nil {
 		status["xiaohongshu_server"] = fmt.Sprintf("❌ Error: %v", err)
 	} else {
 		status["xiaohongshu_server"] = "✅ OK"
 	}
 
 	// Test weather service (try to get weather info)
-	if _, err := o.weatherSvc.GetWeatherInfo(); err != nil {
+	if _, err := o.weatherSvc.GetWeatherInfo(ctx); err != nil {
 		status["weather_service"] = fmt.Sprintf("❌ Error: %v", err)
 	} else {
 		status["weather_service"] = "✅ OK"
@@ -291,5 +635,9 @@ func (o *Orchestrator) GetServiceStatus() map[string]string {
 	// Visitor service (always available as it uses mock data)
 	status["visitor_service"] = "✅ OK"
 
+	status["weather_cache_hit_rate"] = fmt.Sprintf("%.0f%%", o.weatherSvc.CacheHitRate()*100)
+	status["traffic_cache_hit_rate"] = fmt.Sprintf("%.0f%%", o.trafficSvc.CacheHitRate()*100)
+	status["visitor_cache_hit_rate"] = fmt.Sprintf("%.0f%%", o.visitorSvc.CacheHitRate()*100)
+
 	return status
 }
\ No newline at end of file
@@ -0,0 +1,50 @@
+package push
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// Dispatcher fans a Notification out to every registered Notifier,
+// retrying each one independently so a failing sink doesn't stop the
+// others from being tried. Notifiers are tried one after another, so a
+// slow sink (or its retries) delays the ones after it.
+type Dispatcher struct {
+	notifiers  []Notifier
+	maxRetries int
+	backoff    time.Duration
+}
+
+// NewDispatcher creates a dispatcher over the given notifiers with a
+// default retry policy of 2 retries, 5s base backoff.
+func NewDispatcher(notifiers ...Notifier) *Dispatcher {
+	return &Dispatcher{
+		notifiers:  notifiers,
+		maxRetries: 2,
+		backoff:    5 * time.Second,
+	}
+}
+
+// Dispatch sends n to every notifier, retrying each up to maxRetries times
+// with linear backoff before giving up on it and moving to the next.
+func (d *Dispatcher) Dispatch(ctx context.Context, n Notification) {
+	for _, notifier := range d.notifiers {
+		var lastErr error
+		for attempt := 0; attempt <= d.maxRetries; attempt++ {
+			if err := notifier.Send(ctx, n); err != nil {
+				lastErr = err
+				log.Printf("⚠️ Notifier send failed for job %q (attempt %d/%d): %v", n.JobName, attempt+1, d.maxRetries+1, err)
+				if attempt < d.maxRetries {
+					time.Sleep(d.backoff * time.Duration(attempt+1))
+				}
+				continue
+			}
+			lastErr = nil
+			break
+		}
+		if lastErr != nil {
+			log.Printf("❌ Notifier gave up on job %q after %d attempts: %v", n.JobName, d.maxRetries+1, lastErr)
+		}
+	}
+}
@@ -0,0 +1,26 @@
+// Package push delivers scheduler run results to one or more external
+// notification sinks (WeChat official account template messages,
+// Enterprise WeChat bot webhooks, or a generic HTTP webhook).
+package push
+
+import (
+	"context"
+	"time"
+)
+
+// Notification summarizes the outcome of a scheduled run for delivery to
+// a Notifier.
+type Notification struct {
+	JobName      string
+	Success      bool
+	Error        string
+	Duration     time.Duration
+	WeiboSummary string
+	LunarLine    string
+	Timestamp    time.Time
+}
+
+// Notifier delivers a Notification to a single external sink.
+type Notifier interface {
+	Send(ctx context.Context, n Notification) error
+}
@@ -0,0 +1,92 @@
+package push
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// WeChatOANotifier sends WeChat Official Account template messages.
+type WeChatOANotifier struct {
+	AccessToken string
+	TemplateID  string
+	ToUser      string
+	client      *http.Client
+}
+
+// NewWeChatOANotifier creates a notifier that posts template messages via
+// the WeChat Official Account API.
+func NewWeChatOANotifier(accessToken, templateID, toUser string) *WeChatOANotifier {
+	return &WeChatOANotifier{
+		AccessToken: accessToken,
+		TemplateID:  templateID,
+		ToUser:      toUser,
+		client:      &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+type wechatTemplateDataItem struct {
+	Value string `json:"value"`
+}
+
+type wechatTemplateRequest struct {
+	ToUser     string                             `json:"touser"`
+	TemplateID string                             `json:"template_id"`
+	Data       map[string]wechatTemplateDataItem `json:"data"`
+}
+
+type wechatTemplateResponse struct {
+	ErrCode int    `json:"errcode"`
+	ErrMsg  string `json:"errmsg"`
+}
+
+// Send implements Notifier.
+func (n *WeChatOANotifier) Send(ctx context.Context, notification Notification) error {
+	status := "成功"
+	if !notification.Success {
+		status = "失败"
+	}
+
+	payload := wechatTemplateRequest{
+		ToUser:     n.ToUser,
+		TemplateID: n.TemplateID,
+		Data: map[string]wechatTemplateDataItem{
+			"job":      {Value: notification.JobName},
+			"status":   {Value: status},
+			"duration": {Value: notification.Duration.String()},
+			"weibo":    {Value: notification.WeiboSummary},
+			"lunar":    {Value: notification.LunarLine},
+		},
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal WeChat template payload: %w", err)
+	}
+
+	url := fmt.Sprintf("https://api.weixin.qq.com/cgi-bin/message/template/send?access_token=%s", n.AccessToken)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to build WeChat template request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send WeChat template message: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var result wechatTemplateResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("failed to decode WeChat template response: %w", err)
+	}
+	if result.ErrCode != 0 {
+		return fmt.Errorf("WeChat template send failed: errcode=%d errmsg=%s", result.ErrCode, result.ErrMsg)
+	}
+
+	return nil
+}
@@ -0,0 +1,85 @@
+package push
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// WeChatWorkNotifier posts markdown messages to an Enterprise WeChat
+// (企业微信) group bot webhook.
+type WeChatWorkNotifier struct {
+	WebhookURL string
+	client     *http.Client
+}
+
+// NewWeChatWorkNotifier creates a notifier for an Enterprise WeChat bot
+// webhook, e.g. https://qyapi.weixin.qq.com/cgi-bin/webhook/send?key=xxx.
+func NewWeChatWorkNotifier(webhookURL string) *WeChatWorkNotifier {
+	return &WeChatWorkNotifier{
+		WebhookURL: webhookURL,
+		client:     &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+type wechatWorkMarkdown struct {
+	Content string `json:"content"`
+}
+
+type wechatWorkMessage struct {
+	MsgType  string              `json:"msgtype"`
+	Markdown wechatWorkMarkdown `json:"markdown"`
+}
+
+type wechatWorkResponse struct {
+	ErrCode int    `json:"errcode"`
+	ErrMsg  string `json:"errmsg"`
+}
+
+// Send implements Notifier.
+func (n *WeChatWorkNotifier) Send(ctx context.Context, notification Notification) error {
+	status := "✅ 成功"
+	if !notification.Success {
+		status = fmt.Sprintf("❌ 失败：%s", notification.Error)
+	}
+
+	content := fmt.Sprintf(
+		"**%s**\n> 状态：%s\n> 耗时：%s\n> %s\n> 微博摘要：%s",
+		notification.JobName, status, notification.Duration, notification.LunarLine, notification.WeiboSummary,
+	)
+
+	payload := wechatWorkMessage{
+		MsgType:  "markdown",
+		Markdown: wechatWorkMarkdown{Content: content},
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal Enterprise WeChat payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.WebhookURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to build Enterprise WeChat request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send Enterprise WeChat message: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var result wechatWorkResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("failed to decode Enterprise WeChat response: %w", err)
+	}
+	if result.ErrCode != 0 {
+		return fmt.Errorf("Enterprise WeChat send failed: errcode=%d errmsg=%s", result.ErrCode, result.ErrMsg)
+	}
+
+	return nil
+}
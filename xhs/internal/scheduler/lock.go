@@ -0,0 +1,48 @@
+package scheduler
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"syscall"
+)
+
+// fileLock is an exclusive, non-blocking flock(2) lock backed by a file on
+// disk, so two separate *processes* running the same job (e.g. a stray
+// duplicate deployment, or a previous instance that didn't shut down
+// cleanly) can't both execute it concurrently - the in-memory job.running
+// guard only protects against overlap within a single process.
+type fileLock struct {
+	f *os.File
+}
+
+// tryLockFile attempts to take an exclusive lock on dir/name, creating the
+// file if needed. It returns ok=false (not an error) if another process
+// currently holds the lock.
+func tryLockFile(dir, name string) (l *fileLock, ok bool, err error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, false, fmt.Errorf("failed to create lock dir %q: %w", dir, err)
+	}
+
+	path := filepath.Join(dir, name)
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0600)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to open lock file %q: %w", path, err)
+	}
+
+	if err := syscall.Flock(int(f.Fd()), syscall.LOCK_EX|syscall.LOCK_NB); err != nil {
+		f.Close()
+		if err == syscall.EWOULDBLOCK {
+			return nil, false, nil
+		}
+		return nil, false, fmt.Errorf("failed to lock %q: %w", path, err)
+	}
+
+	return &fileLock{f: f}, true, nil
+}
+
+// release drops the lock and closes the file.
+func (l *fileLock) release() error {
+	defer l.f.Close()
+	return syscall.Flock(int(l.f.Fd()), syscall.LOCK_UN)
+}
@@ -4,70 +4,189 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"os"
+	"sort"
+	"sync"
 	"time"
 
+	"github.com/robfig/cron/v3"
+
 	"xiaohongshu-unified/internal/orchestrator"
+	"xiaohongshu-unified/internal/push"
 )
 
-// Scheduler handles daily content generation and publishing
+// JobKind distinguishes a normal workflow run from a notification-only run.
+type JobKind string
+
+const (
+	// JobKindWorkflow runs the full orchestrator workflow (the default).
+	JobKindWorkflow JobKind = ""
+	// JobKindBrief only builds and pushes a notification (lunar line +
+	// latest Weibo summary) without generating or publishing content.
+	JobKindBrief JobKind = "brief"
+)
+
+// JobConfig describes a single named cron job: its schedule, timezone and
+// retry policy. Multiple jobs can run on independent cadences against the
+// same orchestrator (e.g. weibo fetch, lunar fetch, publish).
+type JobConfig struct {
+	Name           string  // unique job name
+	Cron           string  // standard 5-field cron expression
+	TZ             string  // IANA timezone, e.g. "Asia/Shanghai"
+	MaxRetries     int     // retries on top of the initial attempt
+	BackoffSeconds int     // base backoff; actual sleep is backoff * 2^attempt
+	Kind           JobKind // JobKindWorkflow (default) or JobKindBrief
+}
+
+// JobStatus is a point-in-time status report for a registered job.
+type JobStatus struct {
+	Name        string
+	NextRun     time.Time
+	LastSuccess time.Time
+	LastFailure time.Time
+	LastStatus  string
+}
+
+// job tracks the runtime state of a registered JobConfig.
+type job struct {
+	cfg         JobConfig
+	entryID     cron.EntryID
+	lastSuccess time.Time
+	lastFailure time.Time
+	lastStatus  string
+	running     bool // guards against a slow run still executing when the next tick fires
+}
+
+// Scheduler runs any number of named, cron-scheduled jobs against an
+// orchestrator, retrying failed runs with exponential backoff.
 type Scheduler struct {
-	orch *orchestrator.Orchestrator
-	ctx  context.Context
-	cancel context.CancelFunc
+	orch       *orchestrator.Orchestrator
+	cron       *cron.Cron
+	jobs       map[string]*job
+	dispatcher *push.Dispatcher
+	lockDir    string
+	mu         sync.Mutex
+	ctx        context.Context
+	cancel     context.CancelFunc
 }
 
 // New creates a new scheduler instance
 func New(orch *orchestrator.Orchestrator) *Scheduler {
 	ctx, cancel := context.WithCancel(context.Background())
 	return &Scheduler{
-		orch:   orch,
-		ctx:    ctx,
-		cancel: cancel,
+		orch:    orch,
+		cron:    cron.New(),
+		jobs:    make(map[string]*job),
+		lockDir: os.TempDir(),
+		ctx:     ctx,
+		cancel:  cancel,
 	}
 }
 
-// Start begins the daily scheduling loop
-func (s *Scheduler) Start() error {
-	log.Println("🕐 Starting daily scheduler for 8pm Beijing time...")
-	
-	// Beijing timezone
-	beijingTZ, err := time.LoadLocation("Asia/Shanghai")
-	if err != nil {
-		return fmt.Errorf("failed to load Beijing timezone: %w", err)
-	}
-
-	// Calculate next 8pm Beijing time
-	nextRun := s.getNext8PMBeijing(beijingTZ)
-	log.Printf("📅 Next scheduled run: %s", nextRun.Format("2006-01-02 15:04:05 MST"))
-
-	for {
-		select {
-		case <-s.ctx.Done():
-			log.Println("🛑 Scheduler stopped")
-			return nil
-		default:
-			now := time.Now().In(beijingTZ)
-			
-			// Check if it's time to run (within 1 minute window)
-			if s.isTimeToRun(now, nextRun) {
-				log.Printf("⏰ Executing scheduled run at %s", now.Format("2006-01-02 15:04:05 MST"))
-				
-				// Run the workflow
-				if err := s.orch.Run(); err != nil {
-					log.Printf("❌ Scheduled workflow failed: %v", err)
-				} else {
-					log.Println("✅ Scheduled workflow completed successfully")
-				}
-				
-				// Calculate next run (tomorrow 8pm)
-				nextRun = s.getNext8PMBeijing(beijingTZ)
-				log.Printf("📅 Next scheduled run: %s", nextRun.Format("2006-01-02 15:04:05 MST"))
-			}
-			
-			// Sleep for 30 seconds before checking again
-			time.Sleep(30 * time.Second)
+// SetNotifiers configures the push notifiers every job run fans its
+// Notification out to. Passing no notifiers disables push notifications.
+func (s *Scheduler) SetNotifiers(notifiers ...push.Notifier) {
+	if len(notifiers) == 0 {
+		s.dispatcher = nil
+		return
+	}
+	s.dispatcher = push.NewDispatcher(notifiers...)
+}
+
+// SetLockDir changes the directory job lock files are created in (default
+// os.TempDir()). Pass cfg.Settings.StateDir so the lock survives alongside
+// the workflow checkpoint store instead of a volatile tmpfs.
+func (s *Scheduler) SetLockDir(dir string) {
+	s.lockDir = dir
+}
+
+// Start registers jobs and runs the cron engine until Stop is called.
+func (s *Scheduler) Start(jobs []JobConfig) error {
+	log.Println("🕐 Starting cron-driven scheduler...")
+
+	for _, cfg := range jobs {
+		if err := s.AddJob(cfg); err != nil {
+			return err
 		}
 	}
+
+	s.cron.Start()
+	<-s.ctx.Done()
+	log.Println("🛑 Scheduler stopped")
+	s.cron.Stop()
+	return nil
+}
+
+// AddJob registers a new job and schedules it immediately.
+func (s *Scheduler) AddJob(cfg JobConfig) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.jobs[cfg.Name]; exists {
+		return fmt.Errorf("job %q is already registered", cfg.Name)
+	}
+
+	if _, err := time.LoadLocation(cfg.TZ); err != nil {
+		return fmt.Errorf("invalid timezone %q for job %q: %w", cfg.TZ, cfg.Name, err)
+	}
+
+	j := &job{cfg: cfg}
+	spec := fmt.Sprintf("CRON_TZ=%s %s", cfg.TZ, cfg.Cron)
+	entryID, err := s.cron.AddFunc(spec, func() { s.runJob(j) })
+	if err != nil {
+		return fmt.Errorf("failed to schedule job %q (%s): %w", cfg.Name, cfg.Cron, err)
+	}
+
+	j.entryID = entryID
+	s.jobs[cfg.Name] = j
+	log.Printf("📌 Registered job %q: %s %s (max retries %d)", cfg.Name, cfg.Cron, cfg.TZ, cfg.MaxRetries)
+	return nil
+}
+
+// RemoveJob unregisters a job by name.
+func (s *Scheduler) RemoveJob(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	j, ok := s.jobs[name]
+	if !ok {
+		return fmt.Errorf("job %q not found", name)
+	}
+	s.cron.Remove(j.entryID)
+	delete(s.jobs, name)
+	log.Printf("🗑️ Removed job %q", name)
+	return nil
+}
+
+// ListJobs returns a status report for every registered job, sorted by name.
+func (s *Scheduler) ListJobs() []JobStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	statuses := make([]JobStatus, 0, len(s.jobs))
+	for _, j := range s.jobs {
+		statuses = append(statuses, JobStatus{
+			Name:        j.cfg.Name,
+			NextRun:     s.cron.Entry(j.entryID).Next,
+			LastSuccess: j.lastSuccess,
+			LastFailure: j.lastFailure,
+			LastStatus:  j.lastStatus,
+		})
+	}
+	sort.Slice(statuses, func(i, k int) bool { return statuses[i].Name < statuses[k].Name })
+	return statuses
+}
+
+// NextRun returns the next scheduled time for the named job.
+func (s *Scheduler) NextRun(name string) (time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	j, ok := s.jobs[name]
+	if !ok {
+		return time.Time{}, fmt.Errorf("job %q not found", name)
+	}
+	return s.cron.Entry(j.entryID).Next, nil
 }
 
 // Stop gracefully stops the scheduler
@@ -76,38 +195,114 @@ func (s *Scheduler) Stop() {
 	s.cancel()
 }
 
-// getNext8PMBeijing calculates the next 8pm Beijing time
-func (s *Scheduler) getNext8PMBeijing(beijingTZ *time.Location) time.Time {
-	now := time.Now().In(beijingTZ)
-	
-	// Create 8pm today in Beijing timezone
-	target := time.Date(now.Year(), now.Month(), now.Day(), 20, 0, 0, 0, beijingTZ)
-	
-	// If 8pm today has already passed, schedule for tomorrow
-	if now.After(target) {
-		target = target.Add(24 * time.Hour)
+// runJob executes a job, retrying up to MaxRetries times with
+// `BackoffSeconds * 2^attempt` sleeps between attempts, then records the
+// send-then-verify status for later reporting and fans a Notification out
+// to any configured push notifiers. If the previous tick of this same job
+// is still running (e.g. a slow LLM/image-gen step overruns the cron
+// interval), this tick is skipped entirely rather than running concurrently
+// with it. An on-disk lock file (one per job name, under lockDir) guards
+// the same overlap across separate processes, which the in-memory running
+// flag can't see.
+func (s *Scheduler) runJob(j *job) {
+	s.mu.Lock()
+	if j.running {
+		s.mu.Unlock()
+		log.Printf("⏭️ Skipping job %q - previous run is still in progress", j.cfg.Name)
+		return
+	}
+	j.running = true
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		j.running = false
+		s.mu.Unlock()
+	}()
+
+	lock, locked, err := tryLockFile(s.lockDir, "scheduler-"+j.cfg.Name+".lock")
+	if err != nil {
+		log.Printf("⚠️ Job %q: lock file error, proceeding without cross-process protection: %v", j.cfg.Name, err)
+	} else if !locked {
+		log.Printf("⏭️ Skipping job %q - lock file held by another process", j.cfg.Name)
+		return
+	} else {
+		defer lock.release()
+	}
+
+	start := time.Now()
+	var lastErr error
+
+	if j.cfg.Kind == JobKindBrief {
+		lastErr = nil
+	} else {
+		for attempt := 0; attempt <= j.cfg.MaxRetries; attempt++ {
+			log.Printf("⏰ Running job %q (attempt %d/%d)", j.cfg.Name, attempt+1, j.cfg.MaxRetries+1)
+
+			if err := s.orch.Run(s.ctx); err != nil {
+				lastErr = err
+				log.Printf("❌ Job %q failed on attempt %d/%d: %v", j.cfg.Name, attempt+1, j.cfg.MaxRetries+1, err)
+
+				if attempt < j.cfg.MaxRetries {
+					backoff := time.Duration(j.cfg.BackoffSeconds) * time.Second * (1 << uint(attempt))
+					log.Printf("⏳ Retrying job %q in %v", j.cfg.Name, backoff)
+					time.Sleep(backoff)
+				}
+				continue
+			}
+
+			lastErr = nil
+			break
+		}
 	}
-	
-	return target
+
+	s.mu.Lock()
+	if lastErr == nil {
+		j.lastSuccess = time.Now()
+		j.lastStatus = "success"
+	} else {
+		j.lastFailure = time.Now()
+		j.lastStatus = fmt.Sprintf("failed: %v", lastErr)
+	}
+	s.mu.Unlock()
+
+	if lastErr == nil {
+		log.Printf("✅ Job %q completed successfully", j.cfg.Name)
+	} else {
+		log.Printf("💥 Job %q exhausted all retries: %v", j.cfg.Name, lastErr)
+	}
+
+	s.notify(j, start, lastErr)
 }
 
-// isTimeToRun checks if current time is within the execution window
-func (s *Scheduler) isTimeToRun(now, target time.Time) bool {
-	// Allow execution within 1 minute window (20:00:00 - 20:00:59)
-	return now.After(target) && now.Before(target.Add(1*time.Minute))
+// notify builds a Notification for the just-finished job run and fans it
+// out to any configured push notifiers. It's always best-effort: missing
+// Weibo/lunar data never blocks the push.
+func (s *Scheduler) notify(j *job, start time.Time, runErr error) {
+	if s.dispatcher == nil {
+		return
+	}
+
+	n := push.Notification{
+		JobName:   j.cfg.Name,
+		Success:   runErr == nil,
+		Duration:  time.Since(start),
+		Timestamp: time.Now(),
+	}
+	if runErr != nil {
+		n.Error = runErr.Error()
+	}
+	if summary, err := s.orch.WeiboSummary(); err == nil {
+		n.WeiboSummary = summary
+	}
+	if line, err := s.orch.LunarLine(); err == nil {
+		n.LunarLine = line
+	}
+
+	s.dispatcher.Dispatch(s.ctx, n)
 }
 
 // RunOnce executes the workflow immediately (for testing)
 func (s *Scheduler) RunOnce() error {
 	log.Println("🚀 Running workflow immediately...")
-	return s.orch.Run()
+	return s.orch.Run(s.ctx)
 }
-
-// GetNextRunTime returns the next scheduled run time
-func (s *Scheduler) GetNextRunTime() (time.Time, error) {
-	beijingTZ, err := time.LoadLocation("Asia/Shanghai")
-	if err != nil {
-		return time.Time{}, fmt.Errorf("failed to load Beijing timezone: %w", err)
-	}
-	return s.getNext8PMBeijing(beijingTZ), nil
-}
\ No newline at end of file
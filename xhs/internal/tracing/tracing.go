@@ -0,0 +1,66 @@
+// Package tracing wires up OpenTelemetry distributed tracing for the
+// orchestrator workflow and the services it drives, exporting to a
+// Jaeger-compatible OTLP collector (see external doc 10 for the reference
+// Jaeger deployment this targets).
+package tracing
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"xiaohongshu-unified/internal/config"
+)
+
+// Shutdown flushes and stops the global TracerProvider. Callers should
+// invoke it on process exit so in-flight spans aren't dropped.
+type Shutdown func(ctx context.Context) error
+
+// Init configures the global TracerProvider and propagator from
+// cfg.Tracing. If tracing is disabled (or unconfigured), it installs a
+// no-op provider so every Tracer() call elsewhere in the codebase stays
+// cheap and safe to call unconditionally.
+func Init(ctx context.Context, cfg *config.Config) (Shutdown, error) {
+	noop := func(context.Context) error { return nil }
+	if cfg == nil || !cfg.Tracing.Enabled {
+		return noop, nil
+	}
+
+	conn, err := grpc.NewClient(cfg.Tracing.OTLPEndpoint, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return noop, fmt.Errorf("failed to dial OTLP endpoint %q: %w", cfg.Tracing.OTLPEndpoint, err)
+	}
+
+	exporter, err := otlptracegrpc.New(ctx, otlptracegrpc.WithGRPCConn(conn))
+	if err != nil {
+		return noop, fmt.Errorf("failed to create OTLP trace exporter: %w", err)
+	}
+
+	res, err := resource.New(ctx,
+		resource.WithAttributes(semconv.ServiceName(cfg.Tracing.ServiceName)),
+	)
+	if err != nil {
+		return noop, fmt.Errorf("failed to build trace resource: %w", err)
+	}
+
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	)
+
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	return tp.Shutdown, nil
+}
@@ -0,0 +1,126 @@
+package traffic
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"xiaohongshu-unified/internal/config"
+)
+
+// amapProvider fetches live road-level traffic status from the AMap
+// (高德地图) traffic status API:
+// https://lbs.amap.com/api/webservice/guide/api/traffic
+type amapProvider struct {
+	apiKey    string
+	rectangle string
+	client    *http.Client
+}
+
+func newAMapProvider(cfg *config.Config) *amapProvider {
+	return &amapProvider{
+		apiKey:    cfg.TrafficAPI.APIKey,
+		rectangle: cfg.TrafficAPI.Rectangle,
+		client:    &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+type amapStatusResponse struct {
+	Status      string `json:"status"`
+	Info        string `json:"info"`
+	Trafficinfo struct {
+		Description string `json:"description"`
+		Evaluation  struct {
+			Expedite    string `json:"expedite"`
+			Congested   string `json:"congested"`
+			Blocked     string `json:"blocked"`
+			Description string `json:"description"`
+		} `json:"evaluation"`
+		Roads []struct {
+			Name      string `json:"name"`
+			Status    string `json:"status"`
+			Direction string `json:"direction"`
+			Speed     string `json:"speed"`
+		} `json:"roads"`
+	} `json:"trafficinfo"`
+}
+
+func (p *amapProvider) FetchTraffic(ctx context.Context, city string) (*TrafficInfo, error) {
+	params := url.Values{}
+	params.Set("key", p.apiKey)
+	params.Set("rectangle", p.rectangle)
+	params.Set("level", "6")
+	params.Set("extensions", "all")
+
+	reqURL := fmt.Sprintf("https://restapi.amap.com/v3/traffic/status/rectangle?%s", params.Encode())
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("amap request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("amap returned status %d", resp.StatusCode)
+	}
+
+	var parsed amapStatusResponse
+	if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
+		return nil, fmt.Errorf("failed to decode amap response: %w", err)
+	}
+	if parsed.Status != "1" {
+		return nil, fmt.Errorf("amap error: %s", parsed.Info)
+	}
+
+	eval := parsed.Trafficinfo.Evaluation
+	expedite := parsePercentFloat(eval.Expedite)
+	congested := parsePercentFloat(eval.Congested)
+	blocked := parsePercentFloat(eval.Blocked)
+	congestionLevel := congestionLevelFromRatio(expedite, congested, blocked)
+
+	var mainRoads []RoadInfo
+	for _, road := range parsed.Trafficinfo.Roads {
+		status, _ := strconv.Atoi(road.Status)
+		speed, _ := strconv.Atoi(road.Speed)
+		mainRoads = append(mainRoads, RoadInfo{
+			Name:        road.Name,
+			Status:      amapRoadStatus(status),
+			Speed:       speed,
+			Description: road.Direction,
+		})
+	}
+
+	return &TrafficInfo{
+		City:            city,
+		OverallStatus:   getStatusByLevel(congestionLevel),
+		CongestionLevel: congestionLevel,
+		MainRoads:       mainRoads,
+		Recommendation:  eval.Description,
+		Timestamp:       time.Now(),
+	}, nil
+}
+
+// amapRoadStatus maps AMap's per-road status code to our status text.
+// 1:畅通 2:缓行 3:拥堵 4:严重拥堵
+func amapRoadStatus(code int) string {
+	switch code {
+	case 1:
+		return "畅通"
+	case 2:
+		return "缓行"
+	case 3:
+		return "拥堵"
+	case 4:
+		return "严重拥堵"
+	default:
+		return "未知"
+	}
+}
@@ -0,0 +1,111 @@
+package traffic
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"xiaohongshu-unified/internal/config"
+)
+
+// baiduProvider fetches live traffic conditions from the Baidu Maps traffic
+// API (百度地图 traffic/v1).
+type baiduProvider struct {
+	apiKey string
+	client *http.Client
+}
+
+func newBaiduProvider(cfg *config.Config) *baiduProvider {
+	return &baiduProvider{
+		apiKey: cfg.TrafficAPI.APIKey,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+type baiduTrafficResponse struct {
+	Status      int    `json:"status"`
+	Message     string `json:"message"`
+	Description string `json:"description"`
+	Evaluation  struct {
+		Expedite  string `json:"expedite"`
+		Congested string `json:"congestion"`
+		Blocked   string `json:"blocked"`
+	} `json:"evaluation"`
+	RoadTraffic []struct {
+		RoadName  string `json:"road_name"`
+		Status    int    `json:"status"`
+		Direction string `json:"direction"`
+	} `json:"road_traffic"`
+}
+
+func (p *baiduProvider) FetchTraffic(ctx context.Context, city string) (*TrafficInfo, error) {
+	params := url.Values{}
+	params.Set("ak", p.apiKey)
+	params.Set("city_name", city)
+	params.Set("road_grade", "0")
+
+	reqURL := fmt.Sprintf("https://api.map.baidu.com/traffic/v1/around?%s", params.Encode())
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("baidu request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("baidu returned status %d", resp.StatusCode)
+	}
+
+	var parsed baiduTrafficResponse
+	if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
+		return nil, fmt.Errorf("failed to decode baidu response: %w", err)
+	}
+	if parsed.Status != 0 {
+		return nil, fmt.Errorf("baidu error: %s", parsed.Message)
+	}
+
+	eval := parsed.Evaluation
+	congestionLevel := congestionLevelFromRatio(parsePercentFloat(eval.Expedite), parsePercentFloat(eval.Congested), parsePercentFloat(eval.Blocked))
+
+	var mainRoads []RoadInfo
+	for _, road := range parsed.RoadTraffic {
+		mainRoads = append(mainRoads, RoadInfo{
+			Name:        road.RoadName,
+			Status:      baiduRoadStatus(road.Status),
+			Description: road.Direction,
+		})
+	}
+
+	return &TrafficInfo{
+		City:            city,
+		OverallStatus:   getStatusByLevel(congestionLevel),
+		CongestionLevel: congestionLevel,
+		MainRoads:       mainRoads,
+		Recommendation:  parsed.Description,
+		Timestamp:       time.Now(),
+	}, nil
+}
+
+// baiduRoadStatus maps Baidu's per-road status code to our status text.
+// 1:畅通 2:缓行 3:拥堵 4:严重拥堵
+func baiduRoadStatus(code int) string {
+	switch code {
+	case 1:
+		return "畅通"
+	case 2:
+		return "缓行"
+	case 3:
+		return "拥堵"
+	case 4:
+		return "严重拥堵"
+	default:
+		return "未知"
+	}
+}
@@ -0,0 +1,128 @@
+package traffic
+
+import (
+	"context"
+	"time"
+
+	"xiaohongshu-unified/internal/config"
+)
+
+// mockProvider generates deterministic, time-of-day-based traffic data. It's
+// the default Provider so the app keeps working without a real traffic API
+// key configured.
+type mockProvider struct {
+	cfg *config.Config
+}
+
+func newMockProvider(cfg *config.Config) *mockProvider {
+	return &mockProvider{cfg: cfg}
+}
+
+func (p *mockProvider) FetchTraffic(ctx context.Context, city string) (*TrafficInfo, error) {
+	now := time.Now()
+	hour := now.Hour()
+
+	// Determine overall status based on time of day
+	var overallStatus string
+	var congestionLevel int
+	var recommendation string
+
+	switch {
+	case hour >= 7 && hour <= 9: // Morning rush hour
+		overallStatus = "拥堵"
+		congestionLevel = 8
+		recommendation = "早高峰时段，建议错峰出行或选择公共交通"
+	case hour >= 17 && hour <= 19: // Evening rush hour
+		overallStatus = "拥堵"
+		congestionLevel = 9
+		recommendation = "晚高峰时段，道路拥堵严重，建议延后出行"
+	case hour >= 10 && hour <= 16: // Daytime
+		overallStatus = "缓行"
+		congestionLevel = 4
+		recommendation = "白天时段，整体路况良好，适合出行"
+	case hour >= 20 || hour <= 6: // Night time
+		overallStatus = "畅通"
+		congestionLevel = 2
+		recommendation = "夜间时段，道路畅通，出行便利"
+	default:
+		overallStatus = "缓行"
+		congestionLevel = 5
+		recommendation = "路况一般，注意安全驾驶"
+	}
+
+	// Generate main roads info
+	mainRoads := []RoadInfo{
+		{
+			Name:        "三环路",
+			Status:      getStatusByLevel(congestionLevel),
+			Speed:       getSpeedByLevel(congestionLevel),
+			TravelTime:  "45-60分钟",
+			Description: "主要环路，车流量较大",
+		},
+		{
+			Name:        "长安街",
+			Status:      getStatusByLevel(congestionLevel - 1),
+			Speed:       getSpeedByLevel(congestionLevel - 1),
+			TravelTime:  "30-40分钟",
+			Description: "东西主干道，通行状况良好",
+		},
+		{
+			Name:        "京藏高速",
+			Status:      getStatusByLevel(congestionLevel + 1),
+			Speed:       getSpeedByLevel(congestionLevel + 1),
+			TravelTime:  "60-90分钟",
+			Description: "进出京主要通道，易发生拥堵",
+		},
+		{
+			Name:        "中关村大街",
+			Status:      getStatusByLevel(congestionLevel),
+			Speed:       getSpeedByLevel(congestionLevel),
+			TravelTime:  "25-35分钟",
+			Description: "科技园区主干道，上下班时段较拥堵",
+		},
+	}
+
+	// Generate incidents based on congestion level
+	var incidents []TrafficIncident
+	if congestionLevel > 6 {
+		incidents = []TrafficIncident{
+			{
+				Type:        "交通事故",
+				Location:    "三环路国贸桥附近",
+				Description: "两车追尾，占用一条车道",
+				Severity:    "一般",
+				StartTime:   now.Add(-30 * time.Minute).Format("15:04"),
+				EstimatedEnd: now.Add(20 * time.Minute).Format("15:04"),
+			},
+			{
+				Type:        "道路施工",
+				Location:    "京藏高速清河收费站",
+				Description: "路面维修，限制通行",
+				Severity:    "轻微",
+				StartTime:   "09:00",
+				EstimatedEnd: "17:00",
+			},
+		}
+	} else if congestionLevel > 3 {
+		incidents = []TrafficIncident{
+			{
+				Type:        "交通管制",
+				Location:    "天安门广场周边",
+				Description: "临时交通管制，请绕行",
+				Severity:    "轻微",
+				StartTime:   "08:00",
+				EstimatedEnd: "18:00",
+			},
+		}
+	}
+
+	return &TrafficInfo{
+		City:           city,
+		OverallStatus:  overallStatus,
+		CongestionLevel: congestionLevel,
+		MainRoads:      mainRoads,
+		Incidents:      incidents,
+		Recommendation: recommendation,
+		Timestamp:      now,
+	}, nil
+}
@@ -0,0 +1,23 @@
+package traffic
+
+import (
+	"xiaohongshu-unified/internal/config"
+)
+
+// NewProvider selects the traffic Provider configured via
+// cfg.TrafficAPI.Provider. An empty value, "mock", or a missing API key all
+// fall back to the offline mock provider so the rest of the app keeps
+// working without a real traffic API key configured.
+func NewProvider(cfg *config.Config) Provider {
+	switch cfg.TrafficAPI.Provider {
+	case "amap":
+		if cfg.TrafficAPI.APIKey != "" {
+			return newAMapProvider(cfg)
+		}
+	case "baidu":
+		if cfg.TrafficAPI.APIKey != "" {
+			return newBaiduProvider(cfg)
+		}
+	}
+	return newMockProvider(cfg)
+}
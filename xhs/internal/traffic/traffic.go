@@ -1,10 +1,13 @@
 package traffic
 
 import (
+	"context"
 	"fmt"
-	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
+	"xiaohongshu-unified/internal/cache"
 	"xiaohongshu-unified/internal/config"
 )
 
@@ -38,170 +41,80 @@ type TrafficIncident struct {
 	EstimatedEnd string `json:"estimated_end"` // 预计结束时间
 }
 
+// Provider fetches real-time traffic information for a city from a single
+// backend (a map vendor's API, or a local mock). Service selects one
+// Provider based on configuration and caches its responses.
+type Provider interface {
+	FetchTraffic(ctx context.Context, city string) (*TrafficInfo, error)
+}
+
 // Service handles traffic information fetching
 type Service struct {
-	cfg    *config.Config
-	client *http.Client
+	cfg      *config.Config
+	provider Provider
+	cache    *cache.Store
 }
 
-// NewService creates a new traffic service
+// NewService creates a new traffic service. The active Provider is selected
+// from cfg.TrafficAPI.Provider ("amap", "baidu", or "" / "mock").
 func NewService(cfg *config.Config) *Service {
 	return &Service{
-		cfg: cfg,
-		client: &http.Client{
-			Timeout: 30 * time.Second,
-		},
+		cfg:      cfg,
+		provider: NewProvider(cfg),
+		cache:    cache.NewStore(cacheTTL(cfg)),
 	}
 }
 
-// GetTrafficInfo fetches current traffic information
-func (s *Service) GetTrafficInfo() (*TrafficInfo, error) {
-	// For demonstration, we'll generate mock traffic data
-	// In production, you would integrate with real traffic APIs like:
-	// - 高德地图 API
-	// - 百度地图 API
-	// - Google Maps API
-	// - 腾讯地图 API
-
-	return s.generateMockTrafficInfo(), nil
-
-	// Uncomment below for real API integration
-	/*
-	apiURL := fmt.Sprintf("%s/traffic", s.cfg.TrafficAPI.BaseURL)
-	params := url.Values{}
-	params.Add("city", s.cfg.TrafficAPI.City)
-	params.Add("key", s.cfg.TrafficAPI.APIKey)
+// digest keys the cache by (provider, city), so repeated lookups within the
+// TTL window don't hit the upstream API (or regenerate mock data) on every
+// call.
+func (s *Service) digest() string {
+	return s.cfg.TrafficAPI.Provider + "|" + s.cfg.TrafficAPI.City
+}
 
-	fullURL := fmt.Sprintf("%s?%s", apiURL, params.Encode())
+// GetTrafficInfo fetches current traffic information, serving a cached
+// response when one is still fresh for this (provider, city) pair.
+func (s *Service) GetTrafficInfo() (*TrafficInfo, error) {
+	digest := s.digest()
+	if v, ok := s.cache.Get(digest); ok {
+		return v.(*TrafficInfo), nil
+	}
 
-	resp, err := s.client.Get(fullURL)
+	info, err := s.provider.FetchTraffic(context.Background(), s.cfg.TrafficAPI.City)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch traffic data: %w", err)
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("traffic API returned status %d", resp.StatusCode)
-	}
+	s.cache.Set(digest, info)
+	return info, nil
+}
 
-	// Parse response based on your chosen API
-	var trafficInfo TrafficInfo
-	if err := json.NewDecoder(resp.Body).Decode(&trafficInfo); err != nil {
-		return nil, fmt.Errorf("failed to decode traffic response: %w", err)
+// cacheTTL resolves the configured cache TTL, defaulting to 10 minutes.
+func cacheTTL(cfg *config.Config) time.Duration {
+	if cfg.TrafficAPI.CacheTTLSeconds > 0 {
+		return time.Duration(cfg.TrafficAPI.CacheTTLSeconds) * time.Second
 	}
-
-	return &trafficInfo, nil
-	*/
+	return 10 * time.Minute
 }
 
-// generateMockTrafficInfo generates mock traffic data
-func (s *Service) generateMockTrafficInfo() *TrafficInfo {
-	now := time.Now()
-	hour := now.Hour()
-
-	// Determine overall status based on time of day
-	var overallStatus string
-	var congestionLevel int
-	var recommendation string
-
-	switch {
-	case hour >= 7 && hour <= 9: // Morning rush hour
-		overallStatus = "拥堵"
-		congestionLevel = 8
-		recommendation = "早高峰时段，建议错峰出行或选择公共交通"
-	case hour >= 17 && hour <= 19: // Evening rush hour
-		overallStatus = "拥堵"
-		congestionLevel = 9
-		recommendation = "晚高峰时段，道路拥堵严重，建议延后出行"
-	case hour >= 10 && hour <= 16: // Daytime
-		overallStatus = "缓行"
-		congestionLevel = 4
-		recommendation = "白天时段，整体路况良好，适合出行"
-	case hour >= 20 || hour <= 6: // Night time
-		overallStatus = "畅通"
-		congestionLevel = 2
-		recommendation = "夜间时段，道路畅通，出行便利"
-	default:
-		overallStatus = "缓行"
-		congestionLevel = 5
-		recommendation = "路况一般，注意安全驾驶"
-	}
+// CacheHitRate returns the fraction of GetTrafficInfo calls served from
+// cache, for GetServiceStatus to report.
+func (s *Service) CacheHitRate() float64 {
+	return s.cache.HitRate()
+}
 
-	// Generate main roads info
-	mainRoads := []RoadInfo{
-		{
-			Name:        "三环路",
-			Status:      getStatusByLevel(congestionLevel),
-			Speed:       getSpeedByLevel(congestionLevel),
-			TravelTime:  "45-60分钟",
-			Description: "主要环路，车流量较大",
-		},
-		{
-			Name:        "长安街",
-			Status:      getStatusByLevel(congestionLevel - 1),
-			Speed:       getSpeedByLevel(congestionLevel - 1),
-			TravelTime:  "30-40分钟",
-			Description: "东西主干道，通行状况良好",
-		},
-		{
-			Name:        "京藏高速",
-			Status:      getStatusByLevel(congestionLevel + 1),
-			Speed:       getSpeedByLevel(congestionLevel + 1),
-			TravelTime:  "60-90分钟",
-			Description: "进出京主要通道，易发生拥堵",
-		},
-		{
-			Name:        "中关村大街",
-			Status:      getStatusByLevel(congestionLevel),
-			Speed:       getSpeedByLevel(congestionLevel),
-			TravelTime:  "25-35分钟",
-			Description: "科技园区主干道，上下班时段较拥堵",
+// PrefetchSource exposes this service's cache to a PrefetchScheduler, so
+// the current (provider, city) traffic report stays warm across the
+// configured lead time.
+func (s *Service) PrefetchSource() cache.Source {
+	return cache.Source{
+		Name:  "traffic",
+		Store: s.cache,
+		Refresh: func(string) error {
+			_, err := s.GetTrafficInfo()
+			return err
 		},
 	}
-
-	// Generate incidents based on congestion level
-	var incidents []TrafficIncident
-	if congestionLevel > 6 {
-		incidents = []TrafficIncident{
-			{
-				Type:        "交通事故",
-				Location:    "三环路国贸桥附近",
-				Description: "两车追尾，占用一条车道",
-				Severity:    "一般",
-				StartTime:   now.Add(-30 * time.Minute).Format("15:04"),
-				EstimatedEnd: now.Add(20 * time.Minute).Format("15:04"),
-			},
-			{
-				Type:        "道路施工",
-				Location:    "京藏高速清河收费站",
-				Description: "路面维修，限制通行",
-				Severity:    "轻微",
-				StartTime:   "09:00",
-				EstimatedEnd: "17:00",
-			},
-		}
-	} else if congestionLevel > 3 {
-		incidents = []TrafficIncident{
-			{
-				Type:        "交通管制",
-				Location:    "天安门广场周边",
-				Description: "临时交通管制，请绕行",
-				Severity:    "轻微",
-				StartTime:   "08:00",
-				EstimatedEnd: "18:00",
-			},
-		}
-	}
-
-	return &TrafficInfo{
-		City:           s.cfg.TrafficAPI.City,
-		OverallStatus:  overallStatus,
-		CongestionLevel: congestionLevel,
-		MainRoads:      mainRoads,
-		Incidents:      incidents,
-		Recommendation: recommendation,
-		Timestamp:      now,
-	}
 }
 
 // getStatusByLevel returns status string based on congestion level
@@ -237,6 +150,32 @@ func getSpeedByLevel(level int) int {
 	return 60 - (level * 5)
 }
 
+// parsePercentFloat parses a road-share figure reported as a bare number or
+// with a trailing "%" (e.g. AMap and Baidu both return "65.22%"); strconv
+// can't parse the "%" itself, so it's stripped first.
+func parsePercentFloat(s string) float64 {
+	v, _ := strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(s), "%"), 64)
+	return v
+}
+
+// congestionLevelFromRatio converts the expedite/congested/blocked road-share
+// figures reported by map vendors into our 1-10 CongestionLevel scale:
+// round(10 * (1 - expedite/(expedite+congested*2+blocked*3))).
+func congestionLevelFromRatio(expedite, congested, blocked float64) int {
+	denom := expedite + congested*2 + blocked*3
+	if denom <= 0 {
+		return 1
+	}
+	level := int(10*(1-expedite/denom) + 0.5)
+	if level < 1 {
+		level = 1
+	}
+	if level > 10 {
+		level = 10
+	}
+	return level
+}
+
 // GetFormattedTraffic returns traffic information in a human-readable format
 func (t *TrafficInfo) GetFormattedTraffic() string {
 	result := fmt.Sprintf("🚗 %s交通：整体%s（拥堵等级%d/10）\n", t.City, t.OverallStatus, t.CongestionLevel)
@@ -245,7 +184,7 @@ func (t *TrafficInfo) GetFormattedTraffic() string {
 	if len(t.MainRoads) > 0 {
 		result += "\n🛣️ 主要道路：\n"
 		for _, road := range t.MainRoads {
-			result += fmt.Sprintf("• %s：%s（平均%dkm/h，预计%s）\n", 
+			result += fmt.Sprintf("• %s：%s（平均%dkm/h，预计%s）\n",
 				road.Name, road.Status, road.Speed, road.TravelTime)
 		}
 	}
@@ -253,10 +192,10 @@ func (t *TrafficInfo) GetFormattedTraffic() string {
 	if len(t.Incidents) > 0 {
 		result += "\n⚠️ 交通事件：\n"
 		for _, incident := range t.Incidents {
-			result += fmt.Sprintf("• %s：%s（%s，%s开始）\n", 
+			result += fmt.Sprintf("• %s：%s（%s，%s开始）\n",
 				incident.Type, incident.Location, incident.Description, incident.StartTime)
 		}
 	}
 
 	return result
-}
\ No newline at end of file
+}
@@ -0,0 +1,143 @@
+package visitor
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"xiaohongshu-unified/internal/config"
+)
+
+// Source fetches a single day's visitor count from some backend - a real
+// upstream API, a CSV export, or (as a last resort) the heuristic that
+// used to be Service's only behavior.
+type Source interface {
+	FetchDaily(ctx context.Context, date time.Time) (int, error)
+}
+
+// NewSource selects the visitor Source configured via cfg.Visitor.Source.
+// An empty value, "mock", or a missing endpoint/file all fall back to
+// MockSource so the rest of the app keeps working without a real visitor
+// data feed configured.
+func NewSource(cfg *config.Config) Source {
+	switch cfg.Visitor.Source {
+	case "http":
+		if cfg.Visitor.APIURL != "" {
+			return newHTTPSource(cfg)
+		}
+	case "csv":
+		if cfg.Visitor.CSVPath != "" {
+			return newCSVSource(cfg)
+		}
+	}
+	return newMockSource()
+}
+
+// httpSource fetches a day's visitor count from a configurable HTTPS JSON
+// endpoint, queried as "<api_url>?date=YYYY-MM-DD" and expected to respond
+// with {"count": 18342}.
+type httpSource struct {
+	cfg        *config.Config
+	httpClient *http.Client
+}
+
+func newHTTPSource(cfg *config.Config) *httpSource {
+	return &httpSource{
+		cfg:        cfg,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (s *httpSource) FetchDaily(ctx context.Context, date time.Time) (int, error) {
+	url := fmt.Sprintf("%s?date=%s", s.cfg.Visitor.APIURL, date.Format("2006-01-02"))
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create visitor API request: %w", err)
+	}
+	if s.cfg.Visitor.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+s.cfg.Visitor.APIKey)
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to call visitor API: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("visitor API returned status %d", resp.StatusCode)
+	}
+
+	var body struct {
+		Count int `json:"count"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return 0, fmt.Errorf("failed to decode visitor API response: %w", err)
+	}
+	return body.Count, nil
+}
+
+// csvSource reads a day's visitor count from a local CSV export with
+// "date,count" rows (e.g. a daily export from the park's ticketing
+// system), used when no live API is configured.
+type csvSource struct {
+	path string
+}
+
+func newCSVSource(cfg *config.Config) *csvSource {
+	return &csvSource{path: cfg.Visitor.CSVPath}
+}
+
+func (s *csvSource) FetchDaily(ctx context.Context, date time.Time) (int, error) {
+	f, err := os.Open(s.path)
+	if err != nil {
+		return 0, fmt.Errorf("failed to open visitor CSV %q: %w", s.path, err)
+	}
+	defer f.Close()
+
+	target := date.Format("2006-01-02")
+	r := csv.NewReader(bufio.NewReader(f))
+	for {
+		record, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return 0, fmt.Errorf("failed to read visitor CSV %q: %w", s.path, err)
+		}
+		if len(record) < 2 || record[0] != target {
+			continue
+		}
+
+		count, err := strconv.Atoi(record[1])
+		if err != nil {
+			return 0, fmt.Errorf("invalid count %q for %s in %s: %w", record[1], target, s.path, err)
+		}
+		return count, nil
+	}
+	return 0, fmt.Errorf("no visitor count recorded for %s in %s", target, s.path)
+}
+
+// MockSource reproduces the original heuristic: a random count drawn from
+// a hardcoded range for the date's holiday/weekend/weekday bucket. It's
+// the default when no real Source is configured.
+type MockSource struct {
+	rand *rand.Rand
+}
+
+func newMockSource() *MockSource {
+	return &MockSource{rand: rand.New(rand.NewSource(time.Now().UnixNano()))}
+}
+
+func (s *MockSource) FetchDaily(ctx context.Context, date time.Time) (int, error) {
+	_, minVisitors, maxVisitors := dayTypeAndRange(date)
+	return minVisitors + s.rand.Intn(maxVisitors-minVisitors+1), nil
+}
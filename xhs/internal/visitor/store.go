@@ -0,0 +1,109 @@
+package visitor
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// DailyCount is one day's persisted visitor count.
+type DailyCount struct {
+	Date  time.Time
+	Count int
+}
+
+// Store persists each day's visitor count to a local SQLite database, so
+// GetLastNDays/GetSameWeekdayLastMonth can compute trends without re-asking
+// the Source for history it may not even expose.
+type Store struct {
+	db *sql.DB
+}
+
+// OpenStore opens (creating if needed) the SQLite file at dbPath and
+// ensures its schema exists.
+func OpenStore(dbPath string) (*Store, error) {
+	db, err := sql.Open("sqlite3", dbPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open visitor store %q: %w", dbPath, err)
+	}
+
+	if _, err := db.Exec(`CREATE TABLE IF NOT EXISTS daily_counts (
+		date  TEXT PRIMARY KEY,
+		count INTEGER NOT NULL
+	)`); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create visitor schema: %w", err)
+	}
+
+	return &Store{db: db}, nil
+}
+
+// Close closes the underlying database.
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// Save persists count for date, overwriting any value already recorded for
+// that day (e.g. a re-run after a transient Source error).
+func (s *Store) Save(date time.Time, count int) error {
+	_, err := s.db.Exec(
+		`INSERT INTO daily_counts (date, count) VALUES (?, ?)
+		 ON CONFLICT(date) DO UPDATE SET count = excluded.count`,
+		date.Format("2006-01-02"), count,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save visitor count for %s: %w", date.Format("2006-01-02"), err)
+	}
+	return nil
+}
+
+// GetLastNDays returns up to n persisted counts ending today, oldest first,
+// for RenderTrendPNG's line chart.
+func (s *Store) GetLastNDays(n int) ([]DailyCount, error) {
+	rows, err := s.db.Query(`SELECT date, count FROM daily_counts ORDER BY date DESC LIMIT ?`, n)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query visitor history: %w", err)
+	}
+	defer rows.Close()
+
+	var series []DailyCount
+	for rows.Next() {
+		var dateStr string
+		var count int
+		if err := rows.Scan(&dateStr, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan visitor history row: %w", err)
+		}
+		date, err := time.Parse("2006-01-02", dateStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid date %q in visitor store: %w", dateStr, err)
+		}
+		series = append(series, DailyCount{Date: date, Count: count})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read visitor history: %w", err)
+	}
+
+	for i, j := 0, len(series)-1; i < j; i, j = i+1, j-1 {
+		series[i], series[j] = series[j], series[i]
+	}
+	return series, nil
+}
+
+// GetSameWeekdayLastMonth returns the persisted count for the same weekday
+// four weeks before date (the closest "same weekday, a month ago" match
+// without pulling in a full calendar library), for month-over-month
+// comparisons. ok is false if nothing is on record for that day.
+func (s *Store) GetSameWeekdayLastMonth(date time.Time) (count int, ok bool, err error) {
+	target := date.AddDate(0, 0, -28).Format("2006-01-02")
+
+	row := s.db.QueryRow(`SELECT count FROM daily_counts WHERE date = ?`, target)
+	if err := row.Scan(&count); err != nil {
+		if err == sql.ErrNoRows {
+			return 0, false, nil
+		}
+		return 0, false, fmt.Errorf("failed to query visitor count for %s: %w", target, err)
+	}
+	return count, true, nil
+}
@@ -0,0 +1,62 @@
+package visitor
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+
+	"github.com/wcharczuk/go-chart/v2"
+	"github.com/wcharczuk/go-chart/v2/drawing"
+)
+
+// RenderTrendPNG draws series (oldest first) as a line chart at width x
+// height, highlighting the most recent point as today's value, and
+// returns the encoded PNG bytes ready to embed as a cover overlay layer.
+func RenderTrendPNG(series []DailyCount, width, height int) ([]byte, error) {
+	if len(series) == 0 {
+		return nil, fmt.Errorf("no visitor history to chart")
+	}
+
+	xValues := make([]time.Time, len(series))
+	yValues := make([]float64, len(series))
+	for i, d := range series {
+		xValues[i] = d.Date
+		yValues[i] = float64(d.Count)
+	}
+	today := series[len(series)-1]
+
+	graph := chart.Chart{
+		Width:  width,
+		Height: height,
+		Background: chart.Style{
+			FillColor: drawing.ColorTransparent,
+		},
+		Series: []chart.Series{
+			chart.TimeSeries{
+				Name:    "游客量",
+				XValues: xValues,
+				YValues: yValues,
+				Style: chart.Style{
+					StrokeColor: drawing.ColorBlue,
+					StrokeWidth: 2,
+				},
+			},
+			chart.TimeSeries{
+				Name:    "今日",
+				XValues: []time.Time{today.Date},
+				YValues: []float64{float64(today.Count)},
+				Style: chart.Style{
+					StrokeWidth: 0,
+					DotWidth:    5,
+					DotColor:    drawing.ColorRed,
+				},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := graph.Render(chart.PNG, &buf); err != nil {
+		return nil, fmt.Errorf("failed to render visitor trend chart: %w", err)
+	}
+	return buf.Bytes(), nil
+}
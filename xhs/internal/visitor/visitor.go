@@ -1,10 +1,12 @@
 package visitor
 
 import (
+	"context"
 	"fmt"
-	"math/rand"
+	"log"
 	"time"
 
+	"xiaohongshu-unified/internal/cache"
 	"xiaohongshu-unified/internal/config"
 )
 
@@ -14,21 +16,43 @@ type VisitorInfo struct {
 	VisitorCount int       `json:"visitor_count"`
 	DayType      string    `json:"day_type"`
 	Description  string    `json:"description"`
+	TrendSummary string    `json:"trend_summary,omitempty"` // e.g. "比上周同日+12%", empty if not enough history
 	Timestamp    time.Time `json:"timestamp"`
 }
 
 // Service handles visitor information
 type Service struct {
 	config *config.Config
-	rand   *rand.Rand
+	source Source
+	store  *Store // nil if the history store failed to open; trends are then skipped
+	cache  *cache.Store
 }
 
-// NewService creates a new visitor service
+// NewService creates a new visitor service. The active Source is selected
+// via cfg.Visitor.Source (see NewSource); a failure to open the history
+// store behind cfg.Visitor.DBPath only disables trend summaries/charts,
+// it doesn't stop the service from reporting today's count.
 func NewService(cfg *config.Config) *Service {
+	store, err := OpenStore(cfg.Visitor.DBPath)
+	if err != nil {
+		log.Printf("⚠️ visitor: failed to open history store %q, trends disabled: %v", cfg.Visitor.DBPath, err)
+		store = nil
+	}
+
 	return &Service{
 		config: cfg,
-		rand:   rand.New(rand.NewSource(time.Now().UnixNano())),
+		source: NewSource(cfg),
+		store:  store,
+		cache:  cache.NewStore(cacheTTL(cfg)),
+	}
+}
+
+// cacheTTL resolves the configured cache TTL, defaulting to 10 minutes.
+func cacheTTL(cfg *config.Config) time.Duration {
+	if cfg.Settings.CacheTTLSeconds > 0 {
+		return time.Duration(cfg.Settings.CacheTTLSeconds) * time.Second
 	}
+	return 10 * time.Minute
 }
 
 // GetVisitorInfo returns visitor information for today
@@ -36,55 +60,135 @@ func (s *Service) GetVisitorInfo() (*VisitorInfo, error) {
 	return s.GetVisitorInfoForDate(time.Now())
 }
 
-// GetVisitorInfoForDate returns visitor information for a specific date
+// GetVisitorInfoForDate returns visitor information for a specific date,
+// serving a cached response when one is still fresh for that date.
+// Otherwise it asks the configured Source, persists the result to the
+// history store (when one is open), and computes a week-over-week
+// TrendSummary from that history.
 func (s *Service) GetVisitorInfoForDate(date time.Time) (*VisitorInfo, error) {
-	dayType, minVisitors, maxVisitors := s.getDayTypeAndRange(date)
-	
-	// Generate random visitor count within the range
-	visitorCount := minVisitors + s.rand.Intn(maxVisitors-minVisitors+1)
-	
+	digest := date.Format("2006-01-02")
+	if v, ok := s.cache.Get(digest); ok {
+		return v.(*VisitorInfo), nil
+	}
+
+	visitorCount, err := s.source.FetchDaily(context.Background(), date)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch visitor count: %w", err)
+	}
+
+	if s.store != nil {
+		if err := s.store.Save(date, visitorCount); err != nil {
+			log.Printf("⚠️ visitor: failed to persist count for %s: %v", digest, err)
+		}
+	}
+
+	dayType, _, _ := dayTypeAndRange(date)
 	visitorInfo := &VisitorInfo{
 		Date:         date,
 		VisitorCount: visitorCount,
 		DayType:      dayType,
 		Description:  s.generateDescription(dayType, visitorCount),
+		TrendSummary: s.trendSummary(visitorCount),
 		Timestamp:    time.Now(),
 	}
-	
+
+	s.cache.Set(digest, visitorInfo)
 	return visitorInfo, nil
 }
 
-// getDayTypeAndRange determines the day type and visitor count range
-func (s *Service) getDayTypeAndRange(date time.Time) (string, int, int) {
+// trendSummary compares count against the persisted count from exactly a
+// week ago and formats a "比上周同日+12%"-style line for the LLM prompt's
+// copy. It returns "" if there's no history store or not enough history
+// yet to make the comparison.
+func (s *Service) trendSummary(count int) string {
+	if s.store == nil {
+		return ""
+	}
+
+	history, err := s.store.GetLastNDays(8)
+	if err != nil || len(history) < 8 {
+		return ""
+	}
+	lastWeek := history[0] // oldest of the last 8 days == exactly 7 days ago
+	if !lastWeek.Date.Equal(history[len(history)-1].Date.AddDate(0, 0, -7)) {
+		return "" // gaps in the history: the oldest row isn't from a week ago
+	}
+	if lastWeek.Count == 0 {
+		return ""
+	}
+
+	delta := float64(count-lastWeek.Count) / float64(lastWeek.Count) * 100
+	sign := ""
+	if delta >= 0 {
+		sign = "+"
+	}
+	return fmt.Sprintf("比上周同日%s%.0f%%", sign, delta)
+}
+
+// RenderTrendPNG renders the last n days of persisted visitor history as a
+// PNG line chart with today highlighted, for use as a cover overlay layer.
+func (s *Service) RenderTrendPNG(n, width, height int) ([]byte, error) {
+	if s.store == nil {
+		return nil, fmt.Errorf("visitor history store not available")
+	}
+	series, err := s.store.GetLastNDays(n)
+	if err != nil {
+		return nil, err
+	}
+	return RenderTrendPNG(series, width, height)
+}
+
+// CacheHitRate returns the fraction of GetVisitorInfo calls served from
+// cache, for GetServiceStatus to report.
+func (s *Service) CacheHitRate() float64 {
+	return s.cache.HitRate()
+}
+
+// PrefetchSource exposes this service's cache to a PrefetchScheduler, so
+// today's visitor estimate stays warm across the configured lead time.
+func (s *Service) PrefetchSource() cache.Source {
+	return cache.Source{
+		Name:  "visitor",
+		Store: s.cache,
+		Refresh: func(string) error {
+			_, err := s.GetVisitorInfo()
+			return err
+		},
+	}
+}
+
+// dayTypeAndRange determines the day type label and the MockSource visitor
+// count range for a date.
+func dayTypeAndRange(date time.Time) (string, int, int) {
 	month := int(date.Month())
 	day := date.Day()
 	weekday := date.Weekday()
-	
+
 	// Check for National Day Holiday (Oct 1-7)
 	if month == 10 && day >= 1 && day <= 7 {
 		return "国庆节假期", 26000, 35000
 	}
-	
+
 	// Check for May Holiday (May 1-7)
 	if month == 5 && day >= 1 && day <= 7 {
 		return "五一假期", 26000, 35000
 	}
-	
+
 	// Check for Summer Holiday (July and August)
 	if month == 7 || month == 8 {
 		return "暑假", 25000, 30000
 	}
-	
+
 	// Check for Winter Holiday (January 15-31, February 1-15)
 	if (month == 1 && day >= 15) || (month == 2 && day <= 15) {
 		return "寒假", 20000, 25000
 	}
-	
+
 	// Weekend (Saturday and Sunday)
 	if weekday == time.Saturday || weekday == time.Sunday {
 		return "周末", 15000, 21000
 	}
-	
+
 	// Regular weekday
 	return "工作日", 12000, 17000
 }
@@ -104,11 +208,11 @@ func (s *Service) generateDescription(dayType string, visitorCount int) string {
 	default:
 		level = "正常"
 	}
-	
+
 	return fmt.Sprintf("%s，预计游客量%d人，人流量%s", dayType, visitorCount, level)
 }
 
 // GetFormattedVisitor returns a formatted string representation
 func (v *VisitorInfo) GetFormattedVisitor() string {
 	return fmt.Sprintf("👥 游客量：%s", v.Description)
-}
\ No newline at end of file
+}
@@ -0,0 +1,95 @@
+package weather
+
+// hazardTypes maps the first two digits of a CMA alert code to its hazard
+// name.
+var hazardTypes = map[string]string{
+	"01": "台风",
+	"02": "暴雨",
+	"03": "暴雪",
+	"04": "寒潮",
+	"05": "大风",
+	"06": "沙尘暴",
+	"07": "高温",
+	"08": "干旱",
+	"09": "雷电",
+	"10": "冰雹",
+	"11": "霜冻",
+	"12": "大雾",
+	"13": "霾",
+	"14": "道路结冰",
+	"15": "森林火险",
+	"16": "雷雨大风",
+	"17": "春季沙尘",
+	"18": "沙尘",
+}
+
+// alertColors maps the last two digits of a CMA alert code to its severity
+// color.
+var alertColors = map[string]string{
+	"00": "白",
+	"01": "蓝",
+	"02": "黄",
+	"03": "橙",
+	"04": "红",
+}
+
+// AlertType decodes the first two digits of a CMA-style alert code into its
+// hazard name, e.g. "0903" -> "雷电". An unrecognized code returns "未知灾害".
+func AlertType(code string) string {
+	hazardCode := ""
+	if len(code) == 4 {
+		hazardCode = code[:2]
+	}
+	if hazardType, ok := hazardTypes[hazardCode]; ok {
+		return hazardType
+	}
+	return "未知灾害"
+}
+
+// AlertLevel decodes the last two digits of a CMA-style alert code into its
+// severity color, e.g. "0903" -> "橙". An unrecognized code returns "未知".
+func AlertLevel(code string) string {
+	colorCode := ""
+	if len(code) == 4 {
+		colorCode = code[2:]
+	}
+	if color, ok := alertColors[colorCode]; ok {
+		return color
+	}
+	return "未知"
+}
+
+// DecodeAlertCode decodes a CMA-style 4-digit severe weather alert code. An
+// unrecognized code still yields a WeatherAlert with the raw digits surfaced
+// as-is, so callers can display something rather than nothing.
+func DecodeAlertCode(code string) *WeatherAlert {
+	hazardType := AlertType(code)
+	color := AlertLevel(code)
+
+	return &WeatherAlert{
+		Code:        code,
+		HazardType:  hazardType,
+		Color:       color,
+		Description: hazardType + color + "色预警",
+	}
+}
+
+// BackgroundColorForAlert returns a suggested cover background color for the
+// given CMA alert severity color, matching the convention used on CMA
+// warning graphics.
+func BackgroundColorForAlert(color string) string {
+	switch color {
+	case "白":
+		return "#ffffff"
+	case "蓝":
+		return "#1E90FF"
+	case "黄":
+		return "#FFD700"
+	case "橙":
+		return "#FFA500"
+	case "红":
+		return "#FF4500"
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,103 @@
+package weather
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"xiaohongshu-unified/internal/config"
+)
+
+// amapProvider fetches current conditions from AMap's (高德地图) weather
+// info API: https://lbs.amap.com/api/webservice/guide/api/weatherinfo
+type amapProvider struct {
+	apiKey string
+	adcode string
+	client *http.Client
+}
+
+func newAMapProvider(cfg *config.Config, client *http.Client) *amapProvider {
+	return &amapProvider{
+		apiKey: cfg.WeatherAPI.APIKey,
+		adcode: cfg.WeatherAPI.Adcode,
+		client: client,
+	}
+}
+
+func (p *amapProvider) Name() string { return "amap" }
+
+type amapWeatherResponse struct {
+	Status string `json:"status"`
+	Info   string `json:"info"`
+	Lives  []struct {
+		City        string `json:"city"`
+		Weather     string `json:"weather"`
+		Temperature string `json:"temperature"`
+		WindSpeed   string `json:"windpower"`
+		Humidity    string `json:"humidity"`
+	} `json:"lives"`
+}
+
+func (p *amapProvider) Fetch(ctx context.Context, q Query) (*WeatherInfo, error) {
+	if p.apiKey == "" {
+		return nil, fmt.Errorf("amap: no API key configured")
+	}
+
+	adcode := q.Adcode
+	if adcode == "" {
+		adcode = p.adcode
+	}
+
+	params := url.Values{}
+	params.Set("key", p.apiKey)
+	params.Set("city", adcode)
+	params.Set("extensions", "base")
+
+	reqURL := fmt.Sprintf("https://restapi.amap.com/v3/weather/weatherInfo?%s", params.Encode())
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("amap: request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("amap: returned status %d", resp.StatusCode)
+	}
+
+	var parsed amapWeatherResponse
+	if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
+		return nil, fmt.Errorf("amap: failed to decode response: %w", err)
+	}
+	if parsed.Status != "1" || len(parsed.Lives) == 0 {
+		return nil, fmt.Errorf("amap error: %s", parsed.Info)
+	}
+
+	live := parsed.Lives[0]
+	temperature, _ := strconv.ParseFloat(live.Temperature, 64)
+	humidity, _ := strconv.Atoi(live.Humidity)
+	windSpeed, _ := strconv.ParseFloat(live.WindSpeed, 64)
+
+	city := q.City
+	if city == "" {
+		city = live.City
+	}
+
+	return &WeatherInfo{
+		City:        city,
+		Temperature: temperature,
+		FeelsLike:   temperature,
+		Humidity:    humidity,
+		Description: live.Weather,
+		WindSpeed:   windSpeed,
+		Timestamp:   time.Now(),
+	}, nil
+}
@@ -0,0 +1,100 @@
+package weather
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"xiaohongshu-unified/internal/config"
+)
+
+// baiduProvider fetches current conditions and active alerts from Baidu's
+// weather API.
+type baiduProvider struct {
+	apiKey string
+	client *http.Client
+}
+
+func newBaiduProvider(cfg *config.Config, client *http.Client) *baiduProvider {
+	return &baiduProvider{
+		apiKey: cfg.WeatherAPI.APIKey,
+		client: client,
+	}
+}
+
+func (p *baiduProvider) Name() string { return "baidu" }
+
+type baiduWeatherResponse struct {
+	Status int `json:"status"`
+	Result struct {
+		Now struct {
+			Text     string `json:"text"`
+			Temp     int    `json:"temp"`
+			FeelsLike int   `json:"feels_like"`
+			Humidity int    `json:"rh"`
+			WindClass string `json:"wind_class"`
+		} `json:"now"`
+		Alerts []struct {
+			Code        string `json:"code"`
+			Description string `json:"description"`
+		} `json:"alerts"`
+	} `json:"result"`
+}
+
+func (p *baiduProvider) Fetch(ctx context.Context, q Query) (*WeatherInfo, error) {
+	if p.apiKey == "" {
+		return nil, fmt.Errorf("baidu: no API key configured")
+	}
+
+	params := url.Values{}
+	params.Set("ak", p.apiKey)
+	params.Set("district_id", q.Adcode)
+	params.Set("data_type", "all")
+
+	reqURL := fmt.Sprintf("https://api.map.baidu.com/weather/v1/?%s", params.Encode())
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("baidu: request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("baidu: returned status %d", resp.StatusCode)
+	}
+
+	var parsed baiduWeatherResponse
+	if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
+		return nil, fmt.Errorf("baidu: failed to decode response: %w", err)
+	}
+	if parsed.Status != 0 {
+		return nil, fmt.Errorf("baidu error: status %d", parsed.Status)
+	}
+
+	now := parsed.Result.Now
+	info := &WeatherInfo{
+		City:        q.City,
+		Temperature: float64(now.Temp),
+		FeelsLike:   float64(now.FeelsLike),
+		Humidity:    now.Humidity,
+		Description: now.Text,
+		Timestamp:   time.Now(),
+	}
+
+	for _, alert := range parsed.Result.Alerts {
+		decoded := DecodeAlertCode(alert.Code)
+		if alert.Description != "" {
+			decoded.Description = alert.Description
+		}
+		info.Alerts = append(info.Alerts, *decoded)
+	}
+
+	return info, nil
+}
@@ -0,0 +1,281 @@
+package weather
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"xiaohongshu-unified/internal/config"
+)
+
+// caiyunProvider fetches realtime conditions from Caiyun Weather's (彩云天气)
+// v2.6 realtime API. It addresses locations by Lon/Lat rather than city name.
+type caiyunProvider struct {
+	apiKey string
+	lon    float64
+	lat    float64
+	client *http.Client
+}
+
+func newCaiyunProvider(cfg *config.Config, client *http.Client) *caiyunProvider {
+	return &caiyunProvider{
+		apiKey: cfg.WeatherAPI.APIKey,
+		lon:    cfg.WeatherAPI.Lon,
+		lat:    cfg.WeatherAPI.Lat,
+		client: client,
+	}
+}
+
+func (p *caiyunProvider) Name() string { return "caiyun" }
+
+// skyconDescriptions maps Caiyun's skycon enum to a short Chinese description.
+var skyconDescriptions = map[string]string{
+	"CLEAR_DAY":          "晴",
+	"CLEAR_NIGHT":        "晴",
+	"PARTLY_CLOUDY_DAY":  "多云",
+	"PARTLY_CLOUDY_NIGHT": "多云",
+	"CLOUDY":             "阴",
+	"LIGHT_HAZE":         "轻度雾霾",
+	"MODERATE_HAZE":      "中度雾霾",
+	"HEAVY_HAZE":         "重度雾霾",
+	"LIGHT_RAIN":         "小雨",
+	"MODERATE_RAIN":      "中雨",
+	"HEAVY_RAIN":         "大雨",
+	"STORM_RAIN":         "暴雨",
+	"FOG":                "雾",
+	"LIGHT_SNOW":         "小雪",
+	"MODERATE_SNOW":      "中雪",
+	"HEAVY_SNOW":         "大雪",
+	"STORM_SNOW":         "暴雪",
+	"DUST":               "浮尘",
+	"SAND":               "沙尘",
+	"WIND":               "大风",
+}
+
+type caiyunResponse struct {
+	Status string `json:"status"`
+	Result struct {
+		Realtime struct {
+			Temperature float64 `json:"temperature"`
+			Humidity    float64 `json:"humidity"`
+			Skycon      string  `json:"skycon"`
+			Wind        struct {
+				Speed float64 `json:"speed"`
+			} `json:"wind"`
+			Visibility float64 `json:"visibility"`
+			AirQuality struct {
+				PM25 float64 `json:"pm25"`
+				PM10 float64 `json:"pm10"`
+				O3   float64 `json:"o3"`
+				NO2  float64 `json:"no2"`
+				SO2  float64 `json:"so2"`
+				CO   float64 `json:"co"`
+				Aqi  struct {
+					Chn float64 `json:"chn"`
+				} `json:"aqi"`
+			} `json:"air_quality"`
+		} `json:"realtime"`
+	} `json:"result"`
+}
+
+func (p *caiyunProvider) Fetch(ctx context.Context, q Query) (*WeatherInfo, error) {
+	if p.apiKey == "" {
+		return nil, fmt.Errorf("caiyun: no API key configured")
+	}
+
+	lon, lat := q.Lon, q.Lat
+	if lon == 0 && lat == 0 {
+		lon, lat = p.lon, p.lat
+	}
+
+	reqURL := fmt.Sprintf("https://api.caiyunapp.com/v2.6/%s/%f,%f/realtime", p.apiKey, lon, lat)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("caiyun: request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("caiyun: returned status %d", resp.StatusCode)
+	}
+
+	var parsed caiyunResponse
+	if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
+		return nil, fmt.Errorf("caiyun: failed to decode response: %w", err)
+	}
+	if parsed.Status != "ok" {
+		return nil, fmt.Errorf("caiyun error: status %q", parsed.Status)
+	}
+
+	realtime := parsed.Result.Realtime
+	description, ok := skyconDescriptions[realtime.Skycon]
+	if !ok {
+		description = "晴"
+	}
+	air := realtime.AirQuality
+
+	return &WeatherInfo{
+		City:        q.City,
+		Temperature: realtime.Temperature,
+		FeelsLike:   realtime.Temperature,
+		Humidity:    int(realtime.Humidity * 100),
+		Description: description,
+		WindSpeed:   realtime.Wind.Speed,
+		Visibility:  int(realtime.Visibility * 1000),
+		AQI: &AQI{
+			Value: int(air.Aqi.Chn),
+			PM25:  air.PM25,
+			PM10:  air.PM10,
+			O3:    air.O3,
+			NO2:   air.NO2,
+			SO2:   air.SO2,
+			CO:    air.CO,
+		},
+		Timestamp: time.Now(),
+	}, nil
+}
+
+// caiyunForecastResponse is the subset of Caiyun's combined "weather"
+// endpoint response (hourly + daily + alert) that we use for forecasts.
+type caiyunForecastResponse struct {
+	Status string `json:"status"`
+	Result struct {
+		Hourly struct {
+			Temperature []struct {
+				Datetime time.Time `json:"datetime"`
+				Value    float64   `json:"value"`
+			} `json:"temperature"`
+			Skycon []struct {
+				Datetime time.Time `json:"datetime"`
+				Value    string    `json:"value"`
+			} `json:"skycon"`
+			Precipitation []struct {
+				Datetime    time.Time `json:"datetime"`
+				Value       float64   `json:"value"`
+				Probability float64   `json:"probability"`
+			} `json:"precipitation"`
+		} `json:"hourly"`
+		Daily struct {
+			Temperature []struct {
+				Date time.Time `json:"date"`
+				Max  float64   `json:"max"`
+				Min  float64   `json:"min"`
+			} `json:"temperature"`
+			Skycon []struct {
+				Date  time.Time `json:"date"`
+				Value string    `json:"value"`
+			} `json:"skycon_08h_20h"`
+			SkyconNight []struct {
+				Date  time.Time `json:"date"`
+				Value string    `json:"value"`
+			} `json:"skycon_20h_32h"`
+			Astro []struct {
+				Date    time.Time `json:"date"`
+				Sunrise struct {
+					Time string `json:"time"`
+				} `json:"sunrise"`
+				Sunset struct {
+					Time string `json:"time"`
+				} `json:"sunset"`
+			} `json:"astro"`
+		} `json:"daily"`
+	} `json:"result"`
+}
+
+// FetchForecast implements ForecastProvider using Caiyun's combined weather
+// endpoint (hourly + daily), which is the only backend here that offers
+// forecast data.
+func (p *caiyunProvider) FetchForecast(ctx context.Context, q Query, days int) (*WeatherInfo, error) {
+	if p.apiKey == "" {
+		return nil, fmt.Errorf("caiyun: no API key configured")
+	}
+	if days <= 0 {
+		days = 1
+	}
+
+	info, err := p.Fetch(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	lon, lat := q.Lon, q.Lat
+	if lon == 0 && lat == 0 {
+		lon, lat = p.lon, p.lat
+	}
+
+	reqURL := fmt.Sprintf("https://api.caiyunapp.com/v2.6/%s/%f,%f/weather?hourlysteps=%d&dailysteps=%d",
+		p.apiKey, lon, lat, days*24, days)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("caiyun: forecast request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("caiyun: forecast returned status %d", resp.StatusCode)
+	}
+
+	var parsed caiyunForecastResponse
+	if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
+		return nil, fmt.Errorf("caiyun: failed to decode forecast response: %w", err)
+	}
+	if parsed.Status != "ok" {
+		return nil, fmt.Errorf("caiyun forecast error: status %q", parsed.Status)
+	}
+
+	hourly := parsed.Result.Hourly
+	for i, temp := range hourly.Temperature {
+		point := HourPoint{
+			DateTime:            temp.Datetime,
+			Temperature:         temp.Value,
+			ApparentTemperature: temp.Value,
+		}
+		if i < len(hourly.Skycon) {
+			point.Skycon = hourly.Skycon[i].Value
+		}
+		if i < len(hourly.Precipitation) {
+			point.Precipitation.Value = hourly.Precipitation[i].Value
+			point.Precipitation.Probability = hourly.Precipitation[i].Probability
+		}
+		info.Hourly = append(info.Hourly, point)
+	}
+
+	daily := parsed.Result.Daily
+	for i, temp := range daily.Temperature {
+		point := DayPoint{
+			Date: temp.Date,
+			High: temp.Max,
+			Low:  temp.Min,
+		}
+		if i < len(daily.Skycon) {
+			point.SkyconDay = daily.Skycon[i].Value
+		}
+		if i < len(daily.SkyconNight) {
+			point.SkyconNight = daily.SkyconNight[i].Value
+		}
+		if i < len(daily.Astro) {
+			astro := daily.Astro[i]
+			if t, err := time.Parse("15:04", astro.Sunrise.Time); err == nil {
+				point.Sunrise = time.Date(point.Date.Year(), point.Date.Month(), point.Date.Day(), t.Hour(), t.Minute(), 0, 0, point.Date.Location())
+			}
+			if t, err := time.Parse("15:04", astro.Sunset.Time); err == nil {
+				point.Sunset = time.Date(point.Date.Year(), point.Date.Month(), point.Date.Day(), t.Hour(), t.Minute(), 0, 0, point.Date.Location())
+			}
+		}
+		info.Daily = append(info.Daily, point)
+	}
+
+	return info, nil
+}
@@ -0,0 +1,125 @@
+package weather
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// htmlProvider scrapes a Chinese weather website. It requires no API key,
+// so it's always available; Service uses it when no provider is configured,
+// and MultiProvider races it alongside the other backends. It ignores the
+// Query and always scrapes Beijing's page.
+type htmlProvider struct {
+	client *http.Client
+}
+
+func newHTMLProvider(client *http.Client) *htmlProvider {
+	return &htmlProvider{client: client}
+}
+
+func (p *htmlProvider) Name() string { return "html" }
+
+func (p *htmlProvider) Fetch(ctx context.Context, q Query) (*WeatherInfo, error) {
+	weatherURL := "https://e.weather.com.cn/mweather/101010100.shtml"
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, weatherURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("html: failed to fetch weather data: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("html: weather website returned status %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("html: failed to read response body: %w", err)
+	}
+
+	return parseWeatherFromHTML(string(body))
+}
+
+// parseWeatherFromHTML extracts weather information from HTML content
+func parseWeatherFromHTML(htmlContent string) (*WeatherInfo, error) {
+	// Extract temperature using regex
+	tempRegex := regexp.MustCompile(`<span[^>]*class="[^"]*temp[^"]*"[^>]*>([+-]?\d+)°?</span>`)
+	tempMatches := tempRegex.FindStringSubmatch(htmlContent)
+	var temperature float64 = 20.0 // default
+	if len(tempMatches) > 1 {
+		if temp, err := strconv.ParseFloat(tempMatches[1], 64); err == nil {
+			temperature = temp
+		}
+	}
+
+	// Extract weather description
+	descRegex := regexp.MustCompile(`<span[^>]*class="[^"]*weather[^"]*"[^>]*>([^<]+)</span>`)
+	descMatches := descRegex.FindStringSubmatch(htmlContent)
+	description := "晴"
+	if len(descMatches) > 1 {
+		description = strings.TrimSpace(descMatches[1])
+	}
+
+	// Extract humidity
+	humidityRegex := regexp.MustCompile(`湿度[：:]?\s*(\d+)%`)
+	humidityMatches := humidityRegex.FindStringSubmatch(htmlContent)
+	var humidity int = 60 // default
+	if len(humidityMatches) > 1 {
+		if h, err := strconv.Atoi(humidityMatches[1]); err == nil {
+			humidity = h
+		}
+	}
+
+	// Extract wind information
+	windRegex := regexp.MustCompile(`风[力速][：:]?\s*(\d+)[级m/s]`)
+	windMatches := windRegex.FindStringSubmatch(htmlContent)
+	var windSpeed float64 = 3.0 // default
+	if len(windMatches) > 1 {
+		if w, err := strconv.ParseFloat(windMatches[1], 64); err == nil {
+			windSpeed = w
+		}
+	}
+
+	// Extract AQI, published on the same page as a "空气质量指数" figure
+	aqiRegex := regexp.MustCompile(`空气质量指数[：:]?\s*(\d+)`)
+	aqiMatches := aqiRegex.FindStringSubmatch(htmlContent)
+	aqi := 50 // default: 优
+	if len(aqiMatches) > 1 {
+		if a, err := strconv.Atoi(aqiMatches[1]); err == nil {
+			aqi = a
+		}
+	}
+
+	weatherInfo := &WeatherInfo{
+		City:        "北京",
+		Temperature: temperature,
+		FeelsLike:   temperature + 1.0, // approximate feels like
+		Humidity:    humidity,
+		Description: description,
+		WindSpeed:   windSpeed,
+		Visibility:  10000, // default 10km
+		UVIndex:     5.0,   // default moderate
+		AQI:         &AQI{Value: aqi},
+		Timestamp:   time.Now(),
+	}
+
+	// Severe weather alerts are rendered on the page as an icon class named
+	// after the CMA alert code, e.g. class="jb0903".
+	alertRegex := regexp.MustCompile(`jb(\d{4})`)
+	if m := alertRegex.FindStringSubmatch(htmlContent); len(m) > 1 {
+		weatherInfo.Alerts = []WeatherAlert{*DecodeAlertCode(m[1])}
+	}
+
+	return weatherInfo, nil
+}
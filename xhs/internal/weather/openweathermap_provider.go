@@ -0,0 +1,98 @@
+package weather
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"xiaohongshu-unified/internal/config"
+)
+
+// openWeatherMapProvider fetches conditions from OpenWeatherMap's current
+// weather endpoint. OpenWeatherMap has no CMA-style alert codes, so Alerts
+// is always left empty.
+type openWeatherMapProvider struct {
+	apiKey  string
+	baseURL string
+	client  *http.Client
+}
+
+func newOpenWeatherMapProvider(cfg *config.Config, client *http.Client) *openWeatherMapProvider {
+	baseURL := cfg.WeatherAPI.BaseURL
+	if baseURL == "" {
+		baseURL = "https://api.openweathermap.org/data/2.5"
+	}
+	return &openWeatherMapProvider{
+		apiKey:  cfg.WeatherAPI.APIKey,
+		baseURL: baseURL,
+		client:  client,
+	}
+}
+
+func (p *openWeatherMapProvider) Name() string { return "openweathermap" }
+
+type owmResponse struct {
+	Main struct {
+		Temp      float64 `json:"temp"`
+		FeelsLike float64 `json:"feels_like"`
+		Humidity  int     `json:"humidity"`
+	} `json:"main"`
+	Weather []struct {
+		Description string `json:"description"`
+	} `json:"weather"`
+	Wind struct {
+		Speed float64 `json:"speed"`
+	} `json:"wind"`
+	Visibility int `json:"visibility"`
+}
+
+func (p *openWeatherMapProvider) Fetch(ctx context.Context, q Query) (*WeatherInfo, error) {
+	if p.apiKey == "" {
+		return nil, fmt.Errorf("openweathermap: no API key configured")
+	}
+
+	params := url.Values{}
+	params.Set("q", q.City)
+	params.Set("appid", p.apiKey)
+	params.Set("units", "metric")
+
+	reqURL := fmt.Sprintf("%s/weather?%s", p.baseURL, params.Encode())
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("openweathermap: request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("openweathermap: returned status %d", resp.StatusCode)
+	}
+
+	var parsed owmResponse
+	if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
+		return nil, fmt.Errorf("openweathermap: failed to decode response: %w", err)
+	}
+
+	description := "晴"
+	if len(parsed.Weather) > 0 {
+		description = parsed.Weather[0].Description
+	}
+
+	return &WeatherInfo{
+		City:        q.City,
+		Temperature: parsed.Main.Temp,
+		FeelsLike:   parsed.Main.FeelsLike,
+		Humidity:    parsed.Main.Humidity,
+		Description: description,
+		WindSpeed:   parsed.Wind.Speed,
+		Visibility:  parsed.Visibility,
+		Timestamp:   time.Now(),
+	}, nil
+}
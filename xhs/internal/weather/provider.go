@@ -0,0 +1,95 @@
+package weather
+
+import (
+	"context"
+	"fmt"
+)
+
+// Query describes the location a Provider should fetch weather for. Not
+// every Provider needs every field: the HTML scraper ignores it (it always
+// scrapes Beijing's page), AMap wants Adcode, and Caiyun wants Lon/Lat.
+type Query struct {
+	City   string
+	Adcode string // AMap administrative division code
+	Lon    float64
+	Lat    float64
+}
+
+// Provider fetches weather information from a single backend.
+type Provider interface {
+	Name() string
+	Fetch(ctx context.Context, q Query) (*WeatherInfo, error)
+}
+
+// ForecastProvider is implemented by Providers that can additionally supply
+// hourly/daily forecast data (currently only Caiyun). Providers that can't
+// are simply not type-asserted to this interface.
+type ForecastProvider interface {
+	Provider
+	FetchForecast(ctx context.Context, q Query, days int) (*WeatherInfo, error)
+}
+
+// Registry holds configured Providers by name.
+type Registry struct {
+	providers map[string]Provider
+}
+
+// NewRegistry builds a Registry from the given Providers, keyed by their Name().
+func NewRegistry(providers ...Provider) *Registry {
+	r := &Registry{providers: make(map[string]Provider, len(providers))}
+	for _, p := range providers {
+		r.providers[p.Name()] = p
+	}
+	return r
+}
+
+// Register adds (or replaces) a Provider under its own name.
+func (r *Registry) Register(p Provider) {
+	r.providers[p.Name()] = p
+}
+
+// Get looks up a Provider by name.
+func (r *Registry) Get(name string) (Provider, bool) {
+	p, ok := r.providers[name]
+	return p, ok
+}
+
+// MultiProvider races a set of Providers and returns the first successful
+// result, falling back to the next-fastest success if earlier ones error
+// out. It's itself a Provider, named "multi", so it can sit in a Registry
+// alongside the backends it wraps.
+type MultiProvider struct {
+	providers []Provider
+}
+
+// NewMultiProvider builds a MultiProvider over the given backends.
+func NewMultiProvider(providers ...Provider) *MultiProvider {
+	return &MultiProvider{providers: providers}
+}
+
+func (m *MultiProvider) Name() string { return "multi" }
+
+func (m *MultiProvider) Fetch(ctx context.Context, q Query) (*WeatherInfo, error) {
+	type outcome struct {
+		info *WeatherInfo
+		err  error
+	}
+
+	results := make(chan outcome, len(m.providers))
+	for _, p := range m.providers {
+		go func(p Provider) {
+			info, err := p.Fetch(ctx, q)
+			results <- outcome{info, err}
+		}(p)
+	}
+
+	var lastErr error
+	for range m.providers {
+		res := <-results
+		if res.err == nil {
+			return res.info, nil
+		}
+		lastErr = res.err
+	}
+	return nil, fmt.Errorf("all weather providers failed, last error: %w", lastErr)
+}
@@ -0,0 +1,139 @@
+package weather
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"xiaohongshu-unified/internal/cache"
+	"xiaohongshu-unified/internal/config"
+)
+
+// Service handles weather information fetching
+type Service struct {
+	cfg      *config.Config
+	registry *Registry
+	cache    *cache.Store
+}
+
+// NewService creates a new weather service. All backends are registered
+// regardless of configuration; the active one is resolved per-call from
+// cfg.WeatherAPI.Provider ("html" (default), "openweathermap", "caiyun",
+// "amap", "baidu", or "multi").
+func NewService(cfg *config.Config) *Service {
+	client := &http.Client{Timeout: 10 * time.Second}
+
+	html := newHTMLProvider(client)
+	owm := newOpenWeatherMapProvider(cfg, client)
+	caiyun := newCaiyunProvider(cfg, client)
+	amap := newAMapProvider(cfg, client)
+	baidu := newBaiduProvider(cfg, client)
+	multi := NewMultiProvider(html, owm, caiyun, amap, baidu)
+
+	return &Service{
+		cfg:      cfg,
+		registry: NewRegistry(html, owm, caiyun, amap, baidu, multi),
+		cache:    cache.NewStore(cacheTTL(cfg)),
+	}
+}
+
+// cacheTTL resolves the configured cache TTL, defaulting to 10 minutes.
+func cacheTTL(cfg *config.Config) time.Duration {
+	if cfg.WeatherAPI.CacheTTLSeconds > 0 {
+		return time.Duration(cfg.WeatherAPI.CacheTTLSeconds) * time.Second
+	}
+	return 10 * time.Minute
+}
+
+// digest keys the cache by location and day, so a stale entry never masks
+// a later day's forecast.
+func (s *Service) digest() string {
+	return fmt.Sprintf("%s|%s", s.cfg.WeatherAPI.City, time.Now().Format("2006-01-02"))
+}
+
+// GetWeatherInfo fetches current weather information using the configured
+// provider, falling back to the HTML scraper if none is configured. A
+// cached response for today's location is served when still fresh.
+func (s *Service) GetWeatherInfo(ctx context.Context) (*WeatherInfo, error) {
+	digest := s.digest()
+	if v, ok := s.cache.Get(digest); ok {
+		return v.(*WeatherInfo), nil
+	}
+
+	providerName := s.cfg.WeatherAPI.Provider
+	if providerName == "" {
+		providerName = "html"
+	}
+
+	provider, ok := s.registry.Get(providerName)
+	if !ok {
+		return nil, fmt.Errorf("unknown weather provider %q", providerName)
+	}
+
+	query := Query{
+		City:   s.cfg.WeatherAPI.City,
+		Adcode: s.cfg.WeatherAPI.Adcode,
+		Lon:    s.cfg.WeatherAPI.Lon,
+		Lat:    s.cfg.WeatherAPI.Lat,
+	}
+
+	info, err := provider.Fetch(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch weather data: %w", err)
+	}
+	s.cache.Set(digest, info)
+	return info, nil
+}
+
+// CacheHitRate returns the fraction of GetWeatherInfo calls served from
+// cache, for GetServiceStatus to report.
+func (s *Service) CacheHitRate() float64 {
+	return s.cache.HitRate()
+}
+
+// PrefetchSource exposes this service's cache to a PrefetchScheduler, so
+// today's weather stays warm across the configured lead time.
+func (s *Service) PrefetchSource() cache.Source {
+	return cache.Source{
+		Name:  "weather",
+		Store: s.cache,
+		Refresh: func(string) error {
+			_, err := s.GetWeatherInfo(context.Background())
+			return err
+		},
+	}
+}
+
+// GetForecast fetches current conditions plus hourly/daily forecast data for
+// the given number of days, using the configured provider. It returns an
+// error if that provider doesn't implement ForecastProvider.
+func (s *Service) GetForecast(ctx context.Context, days int) (*WeatherInfo, error) {
+	providerName := s.cfg.WeatherAPI.Provider
+	if providerName == "" {
+		providerName = "html"
+	}
+
+	provider, ok := s.registry.Get(providerName)
+	if !ok {
+		return nil, fmt.Errorf("unknown weather provider %q", providerName)
+	}
+
+	forecastProvider, ok := provider.(ForecastProvider)
+	if !ok {
+		return nil, fmt.Errorf("weather provider %q does not support forecasts", providerName)
+	}
+
+	query := Query{
+		City:   s.cfg.WeatherAPI.City,
+		Adcode: s.cfg.WeatherAPI.Adcode,
+		Lon:    s.cfg.WeatherAPI.Lon,
+		Lat:    s.cfg.WeatherAPI.Lat,
+	}
+
+	info, err := forecastProvider.FetchForecast(ctx, query, days)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch weather forecast: %w", err)
+	}
+	return info, nil
+}
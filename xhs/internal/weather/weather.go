@@ -2,14 +2,7 @@ package weather
 
 import (
 	"fmt"
-	"io"
-	"net/http"
-	"regexp"
-	"strconv"
-	"strings"
 	"time"
-
-	"xiaohongshu-unified/internal/config"
 )
 
 // WeatherInfo represents weather information
@@ -22,117 +15,79 @@ type WeatherInfo struct {
 	WindSpeed   float64 `json:"wind_speed"`
 	Visibility  int     `json:"visibility"`
 	UVIndex     float64 `json:"uv_index"`
-	Timestamp   time.Time `json:"timestamp"`
+	AQI         *AQI           `json:"aqi,omitempty"`
+	Alerts      []WeatherAlert `json:"alerts,omitempty"`
+	Hourly      []HourPoint    `json:"hourly,omitempty"`
+	Daily       []DayPoint     `json:"daily,omitempty"`
+	Timestamp   time.Time      `json:"timestamp"`
 }
 
-// Service handles weather information fetching
-type Service struct {
-	cfg    *config.Config
-	client *http.Client
+// AQI is an air quality reading broken down by pollutant, inspired by the
+// Caiyun API's air_quality block.
+type AQI struct {
+	Value int     `json:"value"` // overall index (China MEP "chn" figure)
+	PM25  float64 `json:"pm25"`
+	PM10  float64 `json:"pm10"`
+	O3    float64 `json:"o3"`
+	NO2   float64 `json:"no2"`
+	SO2   float64 `json:"so2"`
+	CO    float64 `json:"co"`
 }
 
-// NewService creates a new weather service
-func NewService(cfg *config.Config) *Service {
-	return &Service{
-		cfg: cfg,
-		client: &http.Client{
-			Timeout: 30 * time.Second,
-		},
+// Level buckets Value into its official Chinese AQI grade.
+func (a *AQI) Level() string {
+	switch {
+	case a.Value <= 50:
+		return "优"
+	case a.Value <= 100:
+		return "良"
+	case a.Value <= 150:
+		return "轻度污染"
+	case a.Value <= 200:
+		return "中度污染"
+	case a.Value <= 300:
+		return "重度污染"
+	default:
+		return "严重污染"
 	}
 }
 
-
-
-// GetWeatherInfo fetches current weather information by scraping Chinese weather website
-func (s *Service) GetWeatherInfo() (*WeatherInfo, error) {
-	// Use the Chinese weather website URL
-	weatherURL := "https://e.weather.com.cn/mweather/101010100.shtml"
-
-	resp, err := s.client.Get(weatherURL)
-	if err != nil {
-		return nil, fmt.Errorf("failed to fetch weather data: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("weather website returned status %d", resp.StatusCode)
-	}
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
-	}
-
-	htmlContent := string(body)
-
-	// Extract weather information using regex patterns
-	weatherInfo, err := s.parseWeatherFromHTML(htmlContent)
-	if err != nil {
-		return nil, fmt.Errorf("failed to parse weather data: %w", err)
-	}
-
-	return weatherInfo, nil
+// WeatherAlert is a decoded CMA-style severe weather alert: the first two
+// digits of Code are the hazard type, the last two the severity color.
+type WeatherAlert struct {
+	Code        string `json:"code"`        // raw 4-digit code, e.g. "0903"
+	HazardType  string `json:"hazard_type"` // e.g. 雷电
+	Color       string `json:"color"`       // 白/蓝/黄/橙/红
+	Description string `json:"description"`
 }
 
-// parseWeatherFromHTML extracts weather information from HTML content
-func (s *Service) parseWeatherFromHTML(htmlContent string) (*WeatherInfo, error) {
-	// Extract temperature using regex
-	tempRegex := regexp.MustCompile(`<span[^>]*class="[^"]*temp[^"]*"[^>]*>([+-]?\d+)°?</span>`)
-	tempMatches := tempRegex.FindStringSubmatch(htmlContent)
-	var temperature float64 = 20.0 // default
-	if len(tempMatches) > 1 {
-		if temp, err := strconv.ParseFloat(tempMatches[1], 64); err == nil {
-			temperature = temp
-		}
-	}
-
-	// Extract weather description
-	descRegex := regexp.MustCompile(`<span[^>]*class="[^"]*weather[^"]*"[^>]*>([^<]+)</span>`)
-	descMatches := descRegex.FindStringSubmatch(htmlContent)
-	description := "晴"
-	if len(descMatches) > 1 {
-		description = strings.TrimSpace(descMatches[1])
-	}
-
-	// Extract humidity
-	humidityRegex := regexp.MustCompile(`湿度[：:]?\s*(\d+)%`)
-	humidityMatches := humidityRegex.FindStringSubmatch(htmlContent)
-	var humidity int = 60 // default
-	if len(humidityMatches) > 1 {
-		if h, err := strconv.Atoi(humidityMatches[1]); err == nil {
-			humidity = h
-		}
-	}
-
-	// Extract wind information
-	windRegex := regexp.MustCompile(`风[力速][：:]?\s*(\d+)[级m/s]`)
-	windMatches := windRegex.FindStringSubmatch(htmlContent)
-	var windSpeed float64 = 3.0 // default
-	if len(windMatches) > 1 {
-		if w, err := strconv.ParseFloat(windMatches[1], 64); err == nil {
-			windSpeed = w
-		}
-	}
-
-	weatherInfo := &WeatherInfo{
-		City:        "北京",
-		Temperature: temperature,
-		FeelsLike:   temperature + 1.0, // approximate feels like
-		Humidity:    humidity,
-		Description: description,
-		WindSpeed:   windSpeed,
-		Visibility:  10000, // default 10km
-		UVIndex:     5.0,   // default moderate
-		Timestamp:   time.Now(),
-	}
+// HourPoint is a single hour of forecast data.
+type HourPoint struct {
+	DateTime            time.Time `json:"datetime"`
+	Temperature         float64   `json:"temperature"`
+	ApparentTemperature float64   `json:"apparent_temperature"`
+	Precipitation       struct {
+		Value       float64 `json:"value"`
+		Probability float64 `json:"probability"`
+	} `json:"precipitation"`
+	Skycon string `json:"skycon"`
+}
 
-	return weatherInfo, nil
+// DayPoint is a single day of forecast data.
+type DayPoint struct {
+	Date        time.Time `json:"date"`
+	Sunrise     time.Time `json:"sunrise"`
+	Sunset      time.Time `json:"sunset"`
+	High        float64   `json:"high"`
+	Low         float64   `json:"low"`
+	SkyconDay   string    `json:"skycon_day"`
+	SkyconNight string    `json:"skycon_night"`
 }
 
 // GetFormattedWeather returns weather information in a human-readable format
 func (w *WeatherInfo) GetFormattedWeather() string {
-	return fmt.Sprintf(
-		"🌤️ %s天气：%s，气温%.1f°C（体感%.1f°C），湿度%d%%，风速%.1fm/s，能见度%dm，紫外线指数%.1f",
+	result := fmt.Sprintf(
+		"🌤️ %s天气：%s，气温%.1f°C（体感%.1f°C），湿度%d%%，风速%.1fm/s，能见度%dm，紫外线指数%.1f\n",
 		w.City,
 		w.Description,
 		w.Temperature,
@@ -142,4 +97,14 @@ func (w *WeatherInfo) GetFormattedWeather() string {
 		w.Visibility,
 		w.UVIndex,
 	)
-}
\ No newline at end of file
+
+	if w.AQI != nil {
+		result += fmt.Sprintf("💨 空气质量：AQI %d（%s）\n", w.AQI.Value, w.AQI.Level())
+	}
+
+	for _, alert := range w.Alerts {
+		result += fmt.Sprintf("⚠️ %s预警（%s）：%s\n", alert.HazardType, alert.Color, alert.Description)
+	}
+
+	return result
+}
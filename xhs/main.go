@@ -1,8 +1,10 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
+	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
@@ -10,9 +12,15 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/sirupsen/logrus"
 
+	"xiaohongshu-unified/internal/admin"
 	"xiaohongshu-unified/internal/config"
+	"xiaohongshu-unified/internal/icalfeed"
+	"xiaohongshu-unified/internal/lunar"
 	"xiaohongshu-unified/internal/orchestrator"
+	"xiaohongshu-unified/internal/push"
 	"xiaohongshu-unified/internal/scheduler"
+	"xiaohongshu-unified/internal/tracing"
+	"xiaohongshu-unified/internal/weibo"
 )
 
 func main() {
@@ -29,6 +37,9 @@ func main() {
 	coverDir := flag.String("cover-dir", "/Users/kx/Desktop", "Output directory for cover images")
 	schedulerMode := flag.Bool("scheduler", false, "Run in scheduler mode (daily 8pm Beijing time)")
 	runOnce := flag.Bool("run-once", false, "Run workflow once and exit")
+	resumeRunID := flag.String("resume", "", "Resume a specific checkpointed run by its run ID instead of starting a new workflow")
+	icalAddr := flag.String("ical-addr", "", "Address to serve the iCal calendar feed on (e.g. :8090); empty disables it")
+	configPath := flag.String("config", "", "Path to config.json (overrides CONFIG_FILE env; defaults to ./config.json)")
 	flag.Parse()
 
 	logrus.Infof("Starting Xiaohongshu Unified Server...")
@@ -37,12 +48,22 @@ func main() {
 	logrus.Infof("Port: %s", *port)
 
 	// Load configuration for content generation
-	cfg, err := config.Load()
+	cfg, err := config.LoadFile(config.ResolvePath(*configPath))
 	if err != nil {
 		logrus.Warnf("Failed to load content generation config: %v", err)
 		// Continue without content generation features
 	}
 
+	shutdownTracing, err := tracing.Init(context.Background(), cfg)
+	if err != nil {
+		logrus.Warnf("Failed to initialize tracing, continuing without it: %v", err)
+	}
+	defer func() {
+		if err := shutdownTracing(context.Background()); err != nil {
+			logrus.Warnf("Failed to flush trace provider: %v", err)
+		}
+	}()
+
 	// Override config with command-line flags if provided
 	if cfg != nil {
 		cfg.MCP.BaseURL = *coverBaseURL
@@ -77,14 +98,65 @@ func main() {
 		logrus.Warn("Content generation orchestrator not available (config not loaded)")
 	}
 
+	// schedulerSvc is shared between scheduler mode and the optional iCal
+	// feed, so the feed can report real NextRun times even when scheduler
+	// mode itself isn't active.
+	var schedulerSvc *scheduler.Scheduler
+	if orch != nil {
+		schedulerSvc = scheduler.New(orch)
+		schedulerSvc.SetNotifiers(buildNotifiers()...)
+		schedulerSvc.SetLockDir(cfg.Settings.StateDir)
+	}
+
+	if cfg != nil && *icalAddr != "" {
+		lunarSvc := lunar.NewService()
+		weiboSvc := weibo.NewService(cfg.Weibo.UID, cfg.Weibo.Cookies, cfg.Weibo.Token)
+		feed := icalfeed.NewFeed(schedulerSvc, lunarSvc, weiboSvc)
+
+		go func() {
+			logrus.Infof("Serving iCal calendar feed on %s/calendar.ics", *icalAddr)
+			mux := http.NewServeMux()
+			mux.Handle("/calendar.ics", feed.Handler())
+			if err := http.ListenAndServe(*icalAddr, mux); err != nil {
+				logrus.Errorf("iCal feed server error: %v", err)
+			}
+		}()
+	}
+
+	if cfg != nil && cfg.Admin.Addr != "" {
+		if orch == nil {
+			logrus.Warn("Admin API not available (config not loaded)")
+		} else {
+			adminServer := admin.NewServer(orch, cfg.Admin.Token)
+			go func() {
+				logrus.Infof("Serving admin API on %s", cfg.Admin.Addr)
+				if err := adminServer.Start(cfg.Admin.Addr); err != nil {
+					logrus.Errorf("Admin API server error: %v", err)
+				}
+			}()
+		}
+	}
+
+	// Handle an explicit resume request before scheduler/run-once mode, so
+	// a crashed run's already-spent LLM/image work isn't paid for twice.
+	if *resumeRunID != "" {
+		if orch == nil {
+			logrus.Fatal("Cannot resume a run without valid configuration")
+		}
+		logrus.Infof("Resuming run %s...", *resumeRunID)
+		if err := orch.Resume(*resumeRunID); err != nil {
+			logrus.Fatalf("Resume failed: %v", err)
+		}
+		logrus.Info("Resumed workflow completed successfully")
+		return
+	}
+
 	// Handle scheduler mode or run-once mode
 	if *schedulerMode || *runOnce {
 		if orch == nil {
 			logrus.Fatal("Cannot run scheduler mode without valid configuration")
 		}
 
-		schedulerSvc := scheduler.New(orch)
-
 		if *runOnce {
 			logrus.Info("Running workflow once...")
 			if err := schedulerSvc.RunOnce(); err != nil {
@@ -98,9 +170,20 @@ func main() {
 		sigChan := make(chan os.Signal, 1)
 		signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
 
+		// Independent cadences for the pieces of the workflow that benefit
+		// from running more often than a single fixed daily job.
+		jobs := []scheduler.JobConfig{
+			{Name: "weibo-fetch", Cron: "0 */2 * * *", TZ: "Asia/Shanghai", MaxRetries: 3, BackoffSeconds: 30},
+			{Name: "lunar-fetch", Cron: "0 6 * * *", TZ: "Asia/Shanghai", MaxRetries: 2, BackoffSeconds: 60},
+			{Name: "publish", Cron: "0 20 * * *", TZ: "Asia/Shanghai", MaxRetries: 3, BackoffSeconds: 60},
+			// Morning brief: push lunar info + latest Weibo summary
+			// without running the full generate-and-publish workflow.
+			{Name: "morning-brief", Cron: "0 7 * * *", TZ: "Asia/Shanghai", MaxRetries: 1, BackoffSeconds: 30, Kind: scheduler.JobKindBrief},
+		}
+
 		// Start scheduler in a goroutine
 		go func() {
-			if err := schedulerSvc.Start(); err != nil {
+			if err := schedulerSvc.Start(jobs); err != nil {
 				logrus.Errorf("Scheduler error: %v", err)
 			}
 		}()
@@ -123,6 +206,7 @@ func main() {
 	logrus.Info("  - publish_content: Publish content to Xiaohongshu")
 	logrus.Info("  - list_feeds: List Xiaohongshu feeds")
 	logrus.Info("  - search_feeds: Search Xiaohongshu feeds")
+	logrus.Info("  - list_cover_templates: List reusable cover template presets")
 
 	if orch != nil {
 		logrus.Info("Available Content Generation APIs:")
@@ -136,6 +220,24 @@ func main() {
 	}
 }
 
+// buildNotifiers assembles the push notifiers configured via environment
+// variables. Any sink left unconfigured is simply omitted.
+func buildNotifiers() []push.Notifier {
+	var notifiers []push.Notifier
+
+	if token, templateID, toUser := os.Getenv("WECHAT_OA_ACCESS_TOKEN"), os.Getenv("WECHAT_OA_TEMPLATE_ID"), os.Getenv("WECHAT_OA_TOUSER"); token != "" && templateID != "" && toUser != "" {
+		notifiers = append(notifiers, push.NewWeChatOANotifier(token, templateID, toUser))
+	}
+	if webhookURL := os.Getenv("WECHAT_WORK_WEBHOOK_URL"); webhookURL != "" {
+		notifiers = append(notifiers, push.NewWeChatWorkNotifier(webhookURL))
+	}
+	if webhookURL := os.Getenv("PUSH_WEBHOOK_URL"); webhookURL != "" {
+		notifiers = append(notifiers, push.NewWebhookNotifier(webhookURL))
+	}
+
+	return notifiers
+}
+
 // UnifiedServer combines MCP server and content generation API
 type UnifiedServer struct {
 	mcpServer *UnifiedMCPServer
@@ -201,7 +303,7 @@ func (s *UnifiedServer) handleGenerateAndPublish(c *gin.Context) {
 	logrus.Info("Starting auto content generation and publishing workflow...")
 
 	// Run the orchestrator workflow
-	err := s.orch.Run()
+	err := s.orch.Run(c.Request.Context())
 	if err != nil {
 		logrus.Errorf("Workflow failed: %v", err)
 		c.JSON(500, gin.H{"error": fmt.Sprintf("Workflow failed: %v", err)})
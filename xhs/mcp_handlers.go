@@ -6,8 +6,12 @@ import (
 	"fmt"
 	"net/url"
 	"strconv"
+	"time"
 
 	"github.com/sirupsen/logrus"
+
+	"xiaohongshu-unified/internal/covertemplate"
+	"xiaohongshu-unified/internal/weather"
 )
 
 const defaultText = `
@@ -44,6 +48,63 @@ func (s *UnifiedMCPServer) handleGenerateXiaohongshuCover(ctx context.Context, a
 		"overlayColor":    "#443c3c",
 		"x":               50,
 		"y":               50,
+		"includeWeather":  false,
+	}
+
+	// A "template" argument resolves a CoverTemplate from s.templateRegistry
+	// and renders its text layer's placeholders (live weather, today's
+	// date, visitor count) into the args a caller would otherwise have had
+	// to hard-code by hand. Its output is applied before the static
+	// defaults below, so a selected template naturally wins over the old
+	// hard-coded banner but explicit caller args still win over both.
+	if templateName, _ := args["template"].(string); templateName != "" {
+		if s.templateRegistry == nil {
+			return &MCPToolResult{
+				Content: []MCPContent{{
+					Type: "text",
+					Text: "cover template registry is not configured",
+				}},
+				IsError: true,
+			}
+		}
+
+		tpl, ok := s.templateRegistry.Get(templateName)
+		if !ok {
+			return &MCPToolResult{
+				Content: []MCPContent{{
+					Type: "text",
+					Text: fmt.Sprintf("unknown cover template %q", templateName),
+				}},
+				IsError: true,
+			}
+		}
+
+		visitors, _ := args["visitors"].(float64)
+		data := covertemplate.Data{
+			Date:     time.Now().Format("1月2日"),
+			Visitors: int(visitors),
+		}
+		if info, err := s.weatherSvc.GetWeatherInfo(ctx); err != nil {
+			logrus.Warnf("MCP: Failed to fetch weather for template %q: %v", templateName, err)
+		} else {
+			data.Weather = info
+		}
+
+		rendered, err := tpl.Render(data)
+		if err != nil {
+			return &MCPToolResult{
+				Content: []MCPContent{{
+					Type: "text",
+					Text: fmt.Sprintf("failed to render cover template %q: %v", templateName, err),
+				}},
+				IsError: true,
+			}
+		}
+		for key, value := range rendered {
+			if _, exists := args[key]; !exists {
+				args[key] = value
+			}
+		}
 	}
 
 	// Apply defaults for missing parameters
@@ -53,6 +114,44 @@ func (s *UnifiedMCPServer) handleGenerateXiaohongshuCover(ctx context.Context, a
 		}
 	}
 
+	// When includeWeather is set, pull current weather/AQI/alert info and
+	// pass it to the cover page as extra URL parameters (AQI grade, today's
+	// high/low and skycon, alert type/color/banner and an alert background
+	// color). The text layer is left as it is: defaultText unless the caller
+	// or a template supplied one.
+	includeWeather, _ := args["includeWeather"].(bool)
+	if includeWeather && s.weatherSvc != nil {
+		info, err := s.weatherSvc.GetForecast(ctx, 1)
+		if err != nil {
+			logrus.Warnf("MCP: Failed to fetch weather forecast for cover, falling back to current conditions: %v", err)
+			info, err = s.weatherSvc.GetWeatherInfo(ctx)
+			if err != nil {
+				logrus.Warnf("MCP: Failed to fetch weather for cover: %v", err)
+				info = nil
+			}
+		}
+		if info != nil {
+			if info.AQI != nil {
+				args["aqiGrade"] = info.AQI.Level()
+			}
+			if len(info.Daily) > 0 {
+				today := info.Daily[0]
+				args["high"] = today.High
+				args["low"] = today.Low
+				args["skycon"] = today.SkyconDay
+			}
+			if len(info.Alerts) > 0 {
+				alert := info.Alerts[0]
+				args["alertType"] = alert.HazardType
+				args["alertColor"] = alert.Color
+				args["alertBanner"] = alert.Description
+				if bg := weather.BackgroundColorForAlert(alert.Color); bg != "" {
+					args["backgroundColor"] = bg
+				}
+			}
+		}
+	}
+
 	// Get output path
 	outputPath, _ := args["output_path"].(string)
 
@@ -62,8 +161,8 @@ func (s *UnifiedMCPServer) handleGenerateXiaohongshuCover(ctx context.Context, a
 
 	// Add all parameters to URL
 	for key, value := range args {
-		if key == "output_path" {
-			continue // Skip output_path as it's not a URL parameter
+		if key == "output_path" || key == "includeWeather" || key == "template" || key == "visitors" {
+			continue // Skip non-visual bookkeeping args; they aren't URL parameters
 		}
 
 		switch v := value.(type) {
@@ -284,4 +383,79 @@ func (s *UnifiedMCPServer) handleSearchFeeds(ctx context.Context, args map[strin
 			Text: string(jsonData),
 		}},
 	}
-}
\ No newline at end of file
+}
+
+// handleGetWeatherAlerts handles the get_weather_alerts tool call
+func (s *UnifiedMCPServer) handleGetWeatherAlerts(ctx context.Context, args map[string]interface{}) *MCPToolResult {
+	logrus.Info("MCP: Getting weather alerts")
+
+	info, err := s.weatherSvc.GetWeatherInfo(ctx)
+	if err != nil {
+		return &MCPToolResult{
+			Content: []MCPContent{{
+				Type: "text",
+				Text: fmt.Sprintf("获取天气预警失败: %v", err),
+			}},
+			IsError: true,
+		}
+	}
+
+	return &MCPToolResult{
+		Content: []MCPContent{{
+			Type: "text",
+			Text: info.GetFormattedWeather(),
+		}},
+	}
+}
+
+// handleListCoverTemplates handles the list_cover_templates tool call,
+// returning the name/description/image of every template s.templateRegistry
+// loaded, so a caller can discover which `template` argument values
+// generate_xiaohongshu_cover will accept.
+func (s *UnifiedMCPServer) handleListCoverTemplates(ctx context.Context, args map[string]interface{}) *MCPToolResult {
+	logrus.Info("MCP: Listing cover templates")
+
+	if s.templateRegistry == nil {
+		return &MCPToolResult{
+			Content: []MCPContent{{
+				Type: "text",
+				Text: "cover template registry is not configured",
+			}},
+			IsError: true,
+		}
+	}
+
+	type templateSummary struct {
+		Name        string `json:"name"`
+		Description string `json:"description"`
+		Image       string `json:"image"`
+	}
+
+	templates := s.templateRegistry.List()
+	summaries := make([]templateSummary, 0, len(templates))
+	for _, tpl := range templates {
+		summaries = append(summaries, templateSummary{
+			Name:        tpl.Name,
+			Description: tpl.Description,
+			Image:       tpl.Image,
+		})
+	}
+
+	jsonData, err := json.MarshalIndent(summaries, "", "  ")
+	if err != nil {
+		return &MCPToolResult{
+			Content: []MCPContent{{
+				Type: "text",
+				Text: fmt.Sprintf("序列化结果失败: %v", err),
+			}},
+			IsError: true,
+		}
+	}
+
+	return &MCPToolResult{
+		Content: []MCPContent{{
+			Type: "text",
+			Text: string(jsonData),
+		}},
+	}
+}
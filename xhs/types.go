@@ -109,8 +109,11 @@ type Feed struct {
 	Title    string `json:"title"`
 	Content  string `json:"content"`
 	Author   string `json:"author"`
-	Likes    int    `json:"likes"`
-	Comments int    `json:"comments"`
+	Likes    int64  `json:"likes"`
+	Comments int64  `json:"comments"`
+	Collects int64  `json:"collects"`
+	Shares   int64  `json:"shares"`
+	CoverURL string `json:"cover_url,omitempty"`
 	URL      string `json:"url"`
 }
 
@@ -103,13 +103,18 @@ func (s *XiaohongshuService) ListFeeds(ctx context.Context) (*FeedsListResponse,
 	// Convert xiaohongshu.Feed to main.Feed
 	mainFeeds := make([]Feed, len(feeds))
 	for i, feed := range feeds {
+		likes, collects, comments, shares := feedCounts(feed.NoteCard.InteractInfo)
+		content, coverURL := feedContent(feed.NoteCard)
 		mainFeeds[i] = Feed{
 			ID:       feed.ID,
 			Title:    feed.NoteCard.DisplayTitle,
-			Content:  feed.NoteCard.DisplayTitle, // Using title as content for now
+			Content:  content,
 			Author:   feed.NoteCard.User.Nickname,
-			Likes:    0, // TODO: Parse from InteractInfo
-			Comments: 0, // TODO: Parse from InteractInfo
+			Likes:    likes,
+			Comments: comments,
+			Collects: collects,
+			Shares:   shares,
+			CoverURL: coverURL,
 			URL:      "https://xiaohongshu.com/explore/" + feed.ID,
 		}
 	}
@@ -141,13 +146,18 @@ func (s *XiaohongshuService) SearchFeeds(ctx context.Context, keyword string) (*
 	// Convert xiaohongshu.Feed to main.Feed
 	mainResults := make([]Feed, len(results))
 	for i, feed := range results {
+		likes, collects, comments, shares := feedCounts(feed.NoteCard.InteractInfo)
+		content, coverURL := feedContent(feed.NoteCard)
 		mainResults[i] = Feed{
 			ID:       feed.ID,
 			Title:    feed.NoteCard.DisplayTitle,
-			Content:  feed.NoteCard.DisplayTitle, // Using title as content for now
+			Content:  content,
 			Author:   feed.NoteCard.User.Nickname,
-			Likes:    0, // TODO: Parse from InteractInfo
-			Comments: 0, // TODO: Parse from InteractInfo
+			Likes:    likes,
+			Comments: comments,
+			Collects: collects,
+			Shares:   shares,
+			CoverURL: coverURL,
 			URL:      "https://xiaohongshu.com/explore/" + feed.ID,
 		}
 	}
@@ -23,16 +23,31 @@ type LunarInfo struct {
 	Timestamp    time.Time `json:"timestamp"`
 }
 
+// Provider resolves lunar calendar information for a given date. Service
+// tries each registered provider in order and falls back to a mock
+// generator only if every provider fails.
+type Provider interface {
+	GetLunarInfoForDate(date time.Time) (*LunarInfo, error)
+}
+
 // Service handles lunar calendar information fetching
 type Service struct {
-	client *http.Client
+	client    *http.Client
+	providers []Provider
 }
 
-// NewService creates a new lunar service
+// NewService creates a new lunar service. It tries the offline algorithmic
+// provider first (no network required, years 1900-2100) and falls back to
+// the HTTP API for dates outside that range.
 func NewService() *Service {
+	client := &http.Client{
+		Timeout: 30 * time.Second,
+	}
 	return &Service{
-		client: &http.Client{
-			Timeout: 30 * time.Second,
+		client: client,
+		providers: []Provider{
+			NewOfflineProvider(),
+			newHTTPProvider(client),
 		},
 	}
 }
@@ -68,12 +83,36 @@ func (s *Service) GetLunarInfo() (*LunarInfo, error) {
 	return s.GetLunarInfoForDate(now)
 }
 
-// GetLunarInfoForDate fetches lunar calendar information for a specific date
+// GetLunarInfoForDate resolves lunar calendar information for a specific
+// date by trying each provider in order, falling back to the mock
+// generator only if all of them fail.
 func (s *Service) GetLunarInfoForDate(date time.Time) (*LunarInfo, error) {
+	for _, provider := range s.providers {
+		if info, err := provider.GetLunarInfoForDate(date); err == nil {
+			return info, nil
+		}
+	}
+
+	// Last resort: every provider failed, fall back to mock data.
+	return s.generateMockLunarInfo(date), nil
+}
+
+// httpProvider fetches lunar calendar information from api.xcvts.cn.
+type httpProvider struct {
+	client *http.Client
+}
+
+// newHTTPProvider creates a provider backed by the remote huangli API.
+func newHTTPProvider(client *http.Client) *httpProvider {
+	return &httpProvider{client: client}
+}
+
+// GetLunarInfoForDate implements Provider.
+func (p *httpProvider) GetLunarInfoForDate(date time.Time) (*LunarInfo, error) {
 	// Use the new lunar calendar API
 	apiURL := "https://api.xcvts.cn/api/huangli"
-	
-	resp, err := s.client.Get(apiURL)
+
+	resp, err := p.client.Get(apiURL)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch lunar data: %w", err)
 	}
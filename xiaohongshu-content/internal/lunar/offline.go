@@ -0,0 +1,309 @@
+package lunar
+
+import (
+	"fmt"
+	"time"
+)
+
+// lunarInfoTable holds one encoded entry per year from 1900 to 2100.
+// Bits 15..4 give the length of lunar months 1..12: bit 15 is month 1 (set
+// = 30 days, clear = 29), ..., bit 4 is month 12. Bit 16 gives the length
+// of that year's leap month the same way (set = 30, clear = 29), and is
+// meaningless when the year has none. Bits 3..0 give the leap-month index,
+// 0 if the year has no leap month that cycle.
+var lunarInfoTable = [201]uint32{
+	0x04bd8, 0x04ae0, 0x0a570, 0x054d5, 0x0d260, 0x0d950, 0x16554, 0x056a0, 0x09ad0, 0x055d2,
+	0x04ae0, 0x0a5b6, 0x0a4d0, 0x0d250, 0x1d255, 0x0b540, 0x0d6a0, 0x0ada2, 0x095b0, 0x14977,
+	0x04970, 0x0a4b0, 0x0b4b5, 0x06a50, 0x06d40, 0x1ab54, 0x02b60, 0x09570, 0x052f2, 0x04970,
+	0x06566, 0x0d4a0, 0x0ea50, 0x06e95, 0x05ad0, 0x02b60, 0x186e3, 0x092e0, 0x1c8d7, 0x0c950,
+	0x0d4a0, 0x1d8a6, 0x0b550, 0x056a0, 0x1a5b4, 0x025d0, 0x092d0, 0x0d2b2, 0x0a950, 0x0b557,
+	0x06ca0, 0x0b550, 0x15355, 0x04da0, 0x0a5d0, 0x14573, 0x052d0, 0x0a9a8, 0x0e950, 0x06aa0,
+	0x0aea6, 0x0ab50, 0x04b60, 0x0aae4, 0x0a570, 0x05260, 0x0f263, 0x0d950, 0x05b57, 0x056a0,
+	0x096d0, 0x04dd5, 0x04ad0, 0x0a4d0, 0x0d4d4, 0x0d250, 0x0d558, 0x0b540, 0x0b5a0, 0x195a6,
+	0x095b0, 0x049b0, 0x0a974, 0x0a4b0, 0x0b27a, 0x06a50, 0x06d40, 0x0af46, 0x0ab60, 0x09570,
+	0x04af5, 0x04970, 0x064b0, 0x074a3, 0x0ea50, 0x06b58, 0x05ac0, 0x0ab60, 0x096d5, 0x092e0,
+	0x0c960, 0x0d954, 0x0d4a0, 0x0da50, 0x07552, 0x056a0, 0x0abb7, 0x025d0, 0x092d0, 0x0cab5,
+	0x0a950, 0x0b4a0, 0x0baa4, 0x0ad50, 0x055d9, 0x04ba0, 0x0a5b0, 0x15176, 0x052b0, 0x0a930,
+	0x07954, 0x06aa0, 0x0ad50, 0x05b52, 0x04b60, 0x0a6e6, 0x0a4e0, 0x0d260, 0x0ea65, 0x0d530,
+	0x05aa0, 0x076a3, 0x096d0, 0x04afb, 0x04ad0, 0x0a4d0, 0x1d0b6, 0x0d250, 0x0d520, 0x0dd45,
+	0x0b5a0, 0x056d0, 0x055b2, 0x049b0, 0x0a577, 0x0a4b0, 0x0aa50, 0x1b255, 0x06d20, 0x0ada0,
+	0x14b63, 0x09370, 0x049f8, 0x04970, 0x064b0, 0x168a6, 0x0ea50, 0x06b20, 0x1a6c4, 0x0aae0,
+	0x0a2e0, 0x0d2e3, 0x0c960, 0x0d557, 0x0d4a0, 0x0da50, 0x05d55, 0x056a0, 0x0a6d0, 0x055d4,
+	0x052d0, 0x0a9b8, 0x0a950, 0x0b4a0, 0x0b6a6, 0x0ad50, 0x055a0, 0x0aba4, 0x0a5b0, 0x052b0,
+	0x0b273, 0x06930, 0x07337, 0x06aa0, 0x0ad50, 0x14b55, 0x04b60, 0x0a570, 0x054e4, 0x0d160,
+	0x0e968, 0x0d520, 0x0daa0, 0x16aa6, 0x056d0, 0x04ae0, 0x0a9d4, 0x0a2d0, 0x0d150, 0x0f252,
+	0x0d520,
+}
+
+// lunarEpoch is the solar date of 1900 正月初一 (lunar new year), the base
+// date the table offsets are measured from.
+var lunarEpoch = time.Date(1900, 1, 31, 0, 0, 0, 0, time.UTC)
+
+const minLunarYear = 1900
+const maxLunarYear = 2100
+
+var zodiacNames = [12]string{"鼠", "牛", "虎", "兔", "龙", "蛇", "马", "羊", "猴", "鸡", "狗", "猪"}
+var tianGan = [10]string{"甲", "乙", "丙", "丁", "戊", "己", "庚", "辛", "壬", "癸"}
+var diZhi = [12]string{"子", "丑", "寅", "卯", "辰", "巳", "午", "未", "申", "酉", "戌", "亥"}
+var lunarMonthNames = [13]string{"", "正月", "二月", "三月", "四月", "五月", "六月", "七月", "八月", "九月", "十月", "冬月", "腊月"}
+var lunarDayNames = [31]string{"",
+	"初一", "初二", "初三", "初四", "初五", "初六", "初七", "初八", "初九", "初十",
+	"十一", "十二", "十三", "十四", "十五", "十六", "十七", "十八", "十九", "二十",
+	"廿一", "廿二", "廿三", "廿四", "廿五", "廿六", "廿七", "廿八", "廿九", "三十",
+}
+
+// solarTermNames lists the 24 solar terms in yearly order starting from 小寒.
+var solarTermNames = [24]string{
+	"小寒", "大寒", "立春", "雨水", "惊蛰", "春分", "清明", "谷雨",
+	"立夏", "小满", "芒种", "夏至", "小暑", "大暑", "立秋", "处暑",
+	"白露", "秋分", "寒露", "霜降", "立冬", "小雪", "大雪", "冬至",
+}
+
+// solarTermBaseMinutes are the minute offsets of each term from the J2000
+// epoch (2000-01-06 02:05 UTC), before the per-year correction is applied.
+var solarTermBaseMinutes = [24]int{
+	0, 21208, 42467, 63836, 85337, 107014, 128867, 150921,
+	173149, 195551, 218072, 240693, 263343, 285989, 308563, 331033,
+	353350, 375494, 397447, 419210, 440795, 462224, 483532, 504758,
+}
+
+var j2000Epoch = time.Date(2000, 1, 6, 2, 5, 0, 0, time.UTC)
+
+// fixedFestivals keys are "MM-DD" in the solar calendar.
+var fixedFestivals = map[string]string{
+	"01-01": "元旦",
+	"03-08": "妇女节",
+	"05-01": "劳动节",
+	"06-01": "儿童节",
+	"09-10": "教师节",
+	"10-01": "国庆节",
+	"12-25": "圣诞节",
+}
+
+// lunarFestivals are keyed by (lunarMonth, lunarDay).
+var lunarFestivals = map[[2]int]string{
+	{1, 1}:   "春节",
+	{1, 15}:  "元宵节",
+	{5, 5}:   "端午节",
+	{7, 7}:   "七夕节",
+	{8, 15}:  "中秋节",
+	{9, 9}:   "重阳节",
+	{12, 8}:  "腊八节",
+}
+
+// OfflineProvider computes LunarInfo for any date between 1900 and 2100
+// using the standard table-driven Chinese lunar algorithm, with no
+// network call.
+type OfflineProvider struct{}
+
+// NewOfflineProvider creates a new offline lunar calendar provider.
+func NewOfflineProvider() *OfflineProvider {
+	return &OfflineProvider{}
+}
+
+// GetLunarInfoForDate implements Provider.
+func (p *OfflineProvider) GetLunarInfoForDate(date time.Time) (*LunarInfo, error) {
+	if date.Year() < minLunarYear || date.Year() > maxLunarYear {
+		return nil, fmt.Errorf("offline lunar provider only supports years %d-%d, got %d", minLunarYear, maxLunarYear, date.Year())
+	}
+
+	lunarYear, lunarMonth, lunarDay, isLeapMonth, err := solarToLunar(date)
+	if err != nil {
+		return nil, err
+	}
+
+	info := &LunarInfo{
+		Date:          date,
+		LunarDate:     formatLunarDate(lunarMonth, lunarDay, isLeapMonth),
+		LunarYear:     formatLunarYear(lunarYear),
+		Zodiac:        zodiacNames[(lunarYear-1900)%12],
+		SolarTerm:     solarTermOnDate(date),
+		Festival:      festivalOn(date, lunarYear, lunarMonth, lunarDay, isLeapMonth),
+		Suit:          []string{},
+		Avoid:         []string{},
+		LuckyColor:    "",
+		LuckyNumber:   "",
+		Constellation: constellationFor(date),
+		Timestamp:     time.Now(),
+	}
+	return info, nil
+}
+
+// leapMonth returns the leap month index for a lunar year (0 if none).
+func leapMonth(y int) int {
+	return int(lunarInfoTable[y-minLunarYear] & 0xf)
+}
+
+// leapDays returns the number of days in the leap month of y (0 if none).
+func leapDays(y int) int {
+	if leapMonth(y) == 0 {
+		return 0
+	}
+	if lunarInfoTable[y-minLunarYear]&0x10000 != 0 {
+		return 30
+	}
+	return 29
+}
+
+// monthDays returns the number of days in lunar month m (1..12) of year y.
+func monthDays(y, m int) int {
+	if lunarInfoTable[y-minLunarYear]&(0x10000>>uint(m)) != 0 {
+		return 30
+	}
+	return 29
+}
+
+// yearDays returns the total number of days in lunar year y, including any
+// leap month.
+func yearDays(y int) int {
+	total := 0
+	for m := 1; m <= 12; m++ {
+		total += monthDays(y, m)
+	}
+	return total + leapDays(y)
+}
+
+// solarToLunar converts a solar date to its lunar year/month/day, reporting
+// whether the resulting month is a leap month.
+func solarToLunar(date time.Time) (year, month, day int, isLeap bool, err error) {
+	utc := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+	offset := int(utc.Sub(lunarEpoch).Hours() / 24)
+	if offset < 0 {
+		return 0, 0, 0, false, fmt.Errorf("date %s is before the supported lunar epoch", date.Format("2006-01-02"))
+	}
+
+	y := minLunarYear
+	for ; y < maxLunarYear; y++ {
+		days := yearDays(y)
+		if offset < days {
+			break
+		}
+		offset -= days
+	}
+
+	leap := leapMonth(y)
+	m := 1
+	for ; m <= 12; m++ {
+		var days int
+		if leap != 0 && m == leap+1 && !isLeap {
+			// Walk through the leap month before moving on to month m.
+			isLeap = true
+			m--
+			days = leapDays(y)
+		} else {
+			if isLeap {
+				isLeap = false
+			}
+			days = monthDays(y, m)
+		}
+		if offset < days {
+			break
+		}
+		offset -= days
+	}
+
+	return y, m, offset + 1, isLeap, nil
+}
+
+func formatLunarDate(month, day int, isLeap bool) string {
+	prefix := ""
+	if isLeap {
+		prefix = "闰"
+	}
+	return prefix + lunarMonthNames[month] + lunarDayNames[day]
+}
+
+func formatLunarYear(y int) string {
+	return tianGan[(y-1984)%10+offsetTo10(y)] + diZhi[(y-1984)%12+offsetTo12(y)] + "年"
+}
+
+// offsetTo10/offsetTo12 keep the Go modulo of a (possibly negative before
+// 1984) difference positive.
+func offsetTo10(y int) int {
+	if (y-1984)%10 < 0 {
+		return 10
+	}
+	return 0
+}
+
+func offsetTo12(y int) int {
+	if (y-1984)%12 < 0 {
+		return 12
+	}
+	return 0
+}
+
+func festivalOn(date time.Time, lunarYear, lunarMonth, lunarDay int, isLeapMonth bool) string {
+	if name, ok := lunarFestivals[[2]int{lunarMonth, lunarDay}]; ok {
+		return name
+	}
+	if isLastDayOfLunarYear(lunarYear, lunarMonth, lunarDay, isLeapMonth) {
+		return "除夕"
+	}
+	if name, ok := fixedFestivals[date.Format("01-02")]; ok {
+		return name
+	}
+	return ""
+}
+
+// isLastDayOfLunarYear reports whether (lunarMonth, lunarDay) - possibly in
+// a leap month - is the final day of lunarYear, i.e. 除夕 (the eve of 春节):
+// the last day of month 12, or of a leap month 12 when lunarYear has one.
+func isLastDayOfLunarYear(lunarYear, lunarMonth, lunarDay int, isLeapMonth bool) bool {
+	if leapMonth(lunarYear) == 12 {
+		return isLeapMonth && lunarMonth == 12 && lunarDay == leapDays(lunarYear)
+	}
+	return !isLeapMonth && lunarMonth == 12 && lunarDay == monthDays(lunarYear, 12)
+}
+
+// solarTermOnDate returns the name of the solar term that falls on date,
+// or "" if date is not a term day.
+func solarTermOnDate(date time.Time) string {
+	y := date.Year()
+	for i, name := range solarTermNames {
+		if solarTermDate(y, i) == date.Format("2006-01-02") {
+			return name
+		}
+	}
+	return ""
+}
+
+// solarTermDate computes the solar date (YYYY-MM-DD) of the n-th term
+// (0-indexed) for year y using a minute-offset table anchored at J2000.
+func solarTermDate(y, n int) string {
+	minutes := int(365.242*float64(y-2000)*24*60) + solarTermBaseMinutes[n]
+	t := j2000Epoch.Add(time.Duration(minutes) * time.Minute)
+	return t.Format("2006-01-02")
+}
+
+// constellationFor derives the western zodiac constellation from the
+// solar month/day.
+func constellationFor(date time.Time) string {
+	m, d := int(date.Month()), date.Day()
+	switch {
+	case (m == 1 && d >= 20) || (m == 2 && d <= 18):
+		return "水瓶座"
+	case (m == 2 && d >= 19) || (m == 3 && d <= 20):
+		return "双鱼座"
+	case (m == 3 && d >= 21) || (m == 4 && d <= 19):
+		return "白羊座"
+	case (m == 4 && d >= 20) || (m == 5 && d <= 20):
+		return "金牛座"
+	case (m == 5 && d >= 21) || (m == 6 && d <= 21):
+		return "双子座"
+	case (m == 6 && d >= 22) || (m == 7 && d <= 22):
+		return "巨蟹座"
+	case (m == 7 && d >= 23) || (m == 8 && d <= 22):
+		return "狮子座"
+	case (m == 8 && d >= 23) || (m == 9 && d <= 22):
+		return "处女座"
+	case (m == 9 && d >= 23) || (m == 10 && d <= 23):
+		return "天秤座"
+	case (m == 10 && d >= 24) || (m == 11 && d <= 22):
+		return "天蝎座"
+	case (m == 11 && d >= 23) || (m == 12 && d <= 21):
+		return "射手座"
+	default:
+		return "摩羯座"
+	}
+}
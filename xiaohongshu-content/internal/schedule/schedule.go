@@ -0,0 +1,256 @@
+// Package schedule persists scheduled Xiaohongshu posts to a local BoltDB
+// store and dispatches them at their scheduled time, so xhs.MCPPublisher.
+// SchedulePost no longer has to post immediately just because the MCP
+// server itself has no notion of "later".
+package schedule
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	bolt "go.etcd.io/bbolt"
+
+	"xiaohongshu-content/internal/xhs"
+)
+
+// Status is a scheduled job's lifecycle state.
+type Status string
+
+const (
+	StatusPending Status = "pending"
+	StatusRunning Status = "running"
+	StatusPosted  Status = "posted"
+	StatusFailed  Status = "failed"
+)
+
+var jobsBucket = []byte("scheduled_posts")
+
+// Job is one scheduled post, persisted to BoltDB keyed by ID so it survives
+// process restarts.
+type Job struct {
+	ID           string          `json:"id"`
+	Request      *xhs.PostRequest `json:"request"`
+	ScheduleTime time.Time       `json:"schedule_time"`
+	TZ           string          `json:"tz"`
+	Status       Status          `json:"status"`
+	LastError    string          `json:"last_error,omitempty"`
+	PostID       string          `json:"post_id,omitempty"`
+	CreatedAt    time.Time       `json:"created_at"`
+	UpdatedAt    time.Time       `json:"updated_at"`
+}
+
+// Queue stores scheduled posts in BoltDB and dispatches due ones to a
+// xhs.Publisher from a background goroutine, polling at pollInterval. Any
+// registered Publisher works - the MCP backend, a MultiPublisher fanning
+// out to several platforms, or a DryRunPublisher in tests.
+type Queue struct {
+	db           *bolt.DB
+	poster       xhs.Publisher
+	pollInterval time.Duration
+	cron         *cron.Cron
+
+	cancel context.CancelFunc
+}
+
+// Open opens (creating if needed) the BoltDB file at dbPath and returns a
+// Queue backed by it. Any jobs left pending from a previous run are picked
+// up automatically the first time Start's dispatcher loop ticks, since
+// dispatch always scans the on-disk bucket rather than an in-memory list.
+func Open(dbPath string, poster xhs.Publisher) (*Queue, error) {
+	db, err := bolt.Open(dbPath, 0600, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open schedule store %q: %w", dbPath, err)
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(jobsBucket)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create schedule bucket: %w", err)
+	}
+
+	return &Queue{
+		db:           db,
+		poster:       poster,
+		pollInterval: 10 * time.Second,
+		cron:         cron.New(),
+	}, nil
+}
+
+// Close stops the dispatcher (if running) and closes the store.
+func (q *Queue) Close() error {
+	if q.cancel != nil {
+		q.cancel()
+	}
+	q.cron.Stop()
+	return q.db.Close()
+}
+
+// Schedule persists a new job for req, to be posted at scheduleTime in tz.
+func (q *Queue) Schedule(req *xhs.PostRequest, scheduleTime time.Time, tz string) (*Job, error) {
+	if _, err := time.LoadLocation(tz); err != nil {
+		return nil, fmt.Errorf("invalid timezone %q: %w", tz, err)
+	}
+
+	now := time.Now()
+	job := &Job{
+		ID:           fmt.Sprintf("job_%d", now.UnixNano()),
+		Request:      req,
+		ScheduleTime: scheduleTime,
+		TZ:           tz,
+		Status:       StatusPending,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+
+	if err := q.put(job); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+// AddRecurring registers a cron rule (e.g. "daily 8pm" -> "0 20 * * *") that,
+// on each fire, builds a fresh PostRequest via build and enqueues it as a
+// concrete scheduled job for that moment - so a single recurring rule keeps
+// producing real, independently cancellable entries in the queue.
+func (q *Queue) AddRecurring(cronExpr, tz string, build func() *xhs.PostRequest) error {
+	loc, err := time.LoadLocation(tz)
+	if err != nil {
+		return fmt.Errorf("invalid timezone %q: %w", tz, err)
+	}
+
+	spec := fmt.Sprintf("CRON_TZ=%s %s", tz, cronExpr)
+	_, err = q.cron.AddFunc(spec, func() {
+		if _, err := q.Schedule(build(), time.Now().In(loc), tz); err != nil {
+			log.Printf("schedule: failed to enqueue recurring post (%s): %v", cronExpr, err)
+		}
+	})
+	return err
+}
+
+// Get returns a single job by ID.
+func (q *Queue) Get(id string) (*Job, error) {
+	var job *Job
+	err := q.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket(jobsBucket).Get([]byte(id))
+		if data == nil {
+			return fmt.Errorf("job %q not found", id)
+		}
+		job = &Job{}
+		return json.Unmarshal(data, job)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+// List returns every persisted job, in no particular order.
+func (q *Queue) List() ([]*Job, error) {
+	var jobs []*Job
+	err := q.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(jobsBucket).ForEach(func(_, data []byte) error {
+			var job Job
+			if err := json.Unmarshal(data, &job); err != nil {
+				return err
+			}
+			jobs = append(jobs, &job)
+			return nil
+		})
+	})
+	return jobs, err
+}
+
+// Cancel deletes a still-pending job. Jobs that are already running, posted,
+// or failed can't be cancelled.
+func (q *Queue) Cancel(id string) error {
+	job, err := q.Get(id)
+	if err != nil {
+		return err
+	}
+	if job.Status != StatusPending {
+		return fmt.Errorf("job %q is %s, not pending", id, job.Status)
+	}
+	return q.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(jobsBucket).Delete([]byte(id))
+	})
+}
+
+// Start runs the cron engine and the dispatcher loop until ctx is cancelled.
+func (q *Queue) Start(ctx context.Context) {
+	ctx, cancel := context.WithCancel(ctx)
+	q.cancel = cancel
+
+	q.cron.Start()
+
+	ticker := time.NewTicker(q.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			q.dispatchDue()
+		}
+	}
+}
+
+// dispatchDue posts every pending job whose ScheduleTime has passed.
+func (q *Queue) dispatchDue() {
+	jobs, err := q.List()
+	if err != nil {
+		log.Printf("schedule: failed to list jobs: %v", err)
+		return
+	}
+
+	now := time.Now()
+	for _, job := range jobs {
+		if job.Status != StatusPending || job.ScheduleTime.After(now) {
+			continue
+		}
+		q.dispatch(job)
+	}
+}
+
+// dispatch posts a single due job and records the outcome.
+func (q *Queue) dispatch(job *Job) {
+	job.Status = StatusRunning
+	job.UpdatedAt = time.Now()
+	if err := q.put(job); err != nil {
+		log.Printf("schedule: failed to mark job %q running: %v", job.ID, err)
+		return
+	}
+
+	resp, err := q.poster.Publish(context.Background(), job.Request)
+	job.UpdatedAt = time.Now()
+	if err != nil {
+		job.Status = StatusFailed
+		job.LastError = err.Error()
+		log.Printf("schedule: job %q failed: %v", job.ID, err)
+	} else {
+		job.Status = StatusPosted
+		job.PostID = resp.PostID
+		log.Printf("schedule: job %q posted as %s", job.ID, resp.PostID)
+	}
+
+	if putErr := q.put(job); putErr != nil {
+		log.Printf("schedule: failed to record outcome of job %q: %v", job.ID, putErr)
+	}
+}
+
+func (q *Queue) put(job *Job) error {
+	data, err := json.Marshal(job)
+	if err != nil {
+		return fmt.Errorf("failed to marshal job %q: %w", job.ID, err)
+	}
+	return q.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(jobsBucket).Put([]byte(job.ID), data)
+	})
+}
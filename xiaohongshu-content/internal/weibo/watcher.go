@@ -0,0 +1,243 @@
+package weibo
+
+import (
+	"container/list"
+	"context"
+	"log"
+	"time"
+)
+
+// defaultLRUSize bounds the number of seen post IDs kept in memory so the
+// watcher never re-emits a post already delivered, without growing
+// unbounded over a long-running process.
+const defaultLRUSize = 500
+
+// defaultActivityWindow and defaultActivityBurst tune the "activity
+// triggered" burst mode when the caller doesn't set one explicitly.
+const (
+	defaultActivityWindow = 10 * time.Minute
+	defaultActivityBurst  = 30 * time.Minute
+)
+
+// TimeRange is a daily, wall-clock window expressed as an offset from
+// midnight, e.g. Start: 9h, End: 22h covers 09:00-22:00 every day.
+type TimeRange struct {
+	Start time.Duration
+	End   time.Duration
+}
+
+// contains reports whether t falls within the range on its own calendar day.
+func (r TimeRange) contains(t time.Time) bool {
+	midnight := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	since := t.Sub(midnight)
+	return since >= r.Start && since < r.End
+}
+
+// WatchOptions configures StartWatcher's polling cadence.
+type WatchOptions struct {
+	// BaseInterval is the slow, steady-state polling interval.
+	BaseInterval time.Duration
+	// BurstInterval is the fast polling interval used during BurstWindows
+	// or after activity-triggered bursts.
+	BurstInterval time.Duration
+	// BurstWindows are daily wall-clock windows (e.g. official posting
+	// hours) during which BurstInterval is always used.
+	BurstWindows []TimeRange
+	// ActivityWindow is how recent a newly seen post must be (by its
+	// Created timestamp) to count as "活跃" and trigger a burst.
+	ActivityWindow time.Duration
+	// ActivityBurst is how long BurstInterval stays in effect after an
+	// activity-triggered burst, before decaying back to BaseInterval.
+	ActivityBurst time.Duration
+	// LRUSize bounds how many seen Mblogids are remembered.
+	LRUSize int
+}
+
+func (o *WatchOptions) setDefaults() {
+	if o.BaseInterval <= 0 {
+		o.BaseInterval = 60 * time.Minute
+	}
+	if o.BurstInterval <= 0 {
+		o.BurstInterval = 5 * time.Minute
+	}
+	if o.ActivityWindow <= 0 {
+		o.ActivityWindow = defaultActivityWindow
+	}
+	if o.ActivityBurst <= 0 {
+		o.ActivityBurst = defaultActivityBurst
+	}
+	if o.LRUSize <= 0 {
+		o.LRUSize = defaultLRUSize
+	}
+}
+
+// inBurstWindow reports whether now falls in one of the configured
+// BurstWindows.
+func (o *WatchOptions) inBurstWindow(now time.Time) bool {
+	for _, w := range o.BurstWindows {
+		if w.contains(now) {
+			return true
+		}
+	}
+	return false
+}
+
+// StartWatcher polls GetLatestPosts on an adaptive cadence: BaseInterval
+// most of the day, BurstInterval during configured BurstWindows, and
+// BurstInterval for ActivityBurst after a newly seen post is detected
+// within ActivityWindow of "now" (activity-triggered mode), decaying back
+// to BaseInterval afterwards. Posts already delivered (tracked by Mblogid
+// in a bounded LRU) are never re-emitted. The returned channel is closed
+// when ctx is cancelled.
+func (s *Service) StartWatcher(ctx context.Context, opts WatchOptions) <-chan WeiboPost {
+	opts.setDefaults()
+
+	s.mu.Lock()
+	if s.seen == nil {
+		s.seen = newLRUSet(opts.LRUSize)
+	}
+	s.mu.Unlock()
+
+	out := make(chan WeiboPost)
+
+	go func() {
+		defer close(out)
+
+		var activityUntil time.Time
+		for {
+			now := time.Now()
+			interval := opts.BaseInterval
+			if opts.inBurstWindow(now) || now.Before(activityUntil) {
+				interval = opts.BurstInterval
+			}
+
+			s.mu.Lock()
+			s.nextFetch = now.Add(interval)
+			s.mu.Unlock()
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(interval):
+			}
+
+			posts, err := s.GetLatestPosts(1)
+
+			s.mu.Lock()
+			s.lastFetch = time.Now()
+			s.mu.Unlock()
+
+			if err != nil {
+				log.Printf("[weibo watcher] fetch failed: %v", err)
+				continue
+			}
+
+			for _, post := range posts {
+				s.mu.Lock()
+				isNew := !s.seen.Contains(post.ID)
+				if isNew {
+					s.seen.Add(post.ID)
+				}
+				s.mu.Unlock()
+
+				if !isNew {
+					continue
+				}
+
+				if recentlyPosted(post, opts.ActivityWindow) {
+					activityUntil = time.Now().Add(opts.ActivityBurst)
+				}
+
+				select {
+				case out <- post:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return out
+}
+
+// recentlyPosted reports whether post.Created parses to a time within
+// window of now. Unparsable timestamps are treated as recent so a
+// format we don't recognize doesn't silently suppress bursts.
+func recentlyPosted(post WeiboPost, window time.Duration) bool {
+	t, err := time.Parse(time.RubyDate, post.Created)
+	if err != nil {
+		return true
+	}
+	return time.Since(t) <= window
+}
+
+// LastFetch returns the time of the most recent watcher fetch attempt.
+func (s *Service) LastFetch() time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.lastFetch
+}
+
+// NextFetch returns the time the watcher's next fetch is scheduled for.
+func (s *Service) NextFetch() time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.nextFetch
+}
+
+// SeenIDs returns the Mblogids currently tracked by the watcher's
+// dedup cache, oldest first.
+func (s *Service) SeenIDs() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.seen == nil {
+		return nil
+	}
+	return s.seen.Keys()
+}
+
+// lruSet is a bounded, insertion-ordered set used to remember seen post
+// IDs without growing unbounded over a long-running watcher.
+type lruSet struct {
+	capacity int
+	order    *list.List
+	index    map[string]*list.Element
+}
+
+func newLRUSet(capacity int) *lruSet {
+	return &lruSet{
+		capacity: capacity,
+		order:    list.New(),
+		index:    make(map[string]*list.Element),
+	}
+}
+
+func (l *lruSet) Contains(id string) bool {
+	_, ok := l.index[id]
+	return ok
+}
+
+func (l *lruSet) Add(id string) {
+	if l.Contains(id) {
+		return
+	}
+	elem := l.order.PushBack(id)
+	l.index[id] = elem
+
+	for l.order.Len() > l.capacity {
+		oldest := l.order.Front()
+		if oldest == nil {
+			break
+		}
+		l.order.Remove(oldest)
+		delete(l.index, oldest.Value.(string))
+	}
+}
+
+func (l *lruSet) Keys() []string {
+	keys := make([]string, 0, l.order.Len())
+	for e := l.order.Front(); e != nil; e = e.Next() {
+		keys = append(keys, e.Value.(string))
+	}
+	return keys
+}
@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"regexp"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -41,6 +42,12 @@ type Service struct {
 	Cookies string
 	Token   string
 	client  *http.Client
+
+	// mu guards the watcher state below, populated by StartWatcher.
+	mu        sync.Mutex
+	seen      *lruSet
+	lastFetch time.Time
+	nextFetch time.Time
 }
 
 // NewService creates a new weibo service
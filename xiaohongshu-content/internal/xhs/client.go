@@ -2,12 +2,14 @@ package xhs
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
-	"log"
 	"net/http"
 	"time"
 
+	"github.com/sirupsen/logrus"
+
 	"xiaohongshu-content/internal/config"
 )
 
@@ -37,6 +39,10 @@ type PostResponse struct {
 	Message     string    `json:"message"`      // Status message
 	PostedAt    time.Time `json:"posted_at"`    // When the post was created
 	Error       string    `json:"error,omitempty"` // Error message if any
+
+	// Results holds one entry per backend when this response came from a
+	// MultiPublisher fan-out; nil for a single-backend publish.
+	Results []PostResponse `json:"results,omitempty"`
 }
 
 // LoginStatusResponse represents the response from checking login status
@@ -78,21 +84,90 @@ type SearchResult struct {
 	URL     string `json:"url"`     // Result URL
 }
 
-// Client handles communication with Xiaohongshu MCP server
-type Client struct {
+// MCPPublisher handles communication with Xiaohongshu MCP server
+type MCPPublisher struct {
 	cfg        *config.Config
 	httpClient *http.Client
 	baseURL    string
+	log        *logrus.Logger
+
+	retryPolicy RetryPolicy
+
+	deadlineTimer
 }
 
-// NewClient creates a new Xiaohongshu client
-func NewClient(cfg *config.Config) *Client {
-	return &Client{
+// Option configures a MCPPublisher.
+type Option func(*MCPPublisher)
+
+// WithLogLevel overrides the client's log level (default info). Use debug
+// to see post titles/content/image URLs in logs - at info they're redacted
+// since MCP call summaries are logged unconditionally and would otherwise
+// put user post bodies in production logs.
+func WithLogLevel(level logrus.Level) Option {
+	return func(c *MCPPublisher) { c.log.SetLevel(level) }
+}
+
+// WithRetryPolicy overrides PostWithRetry's default policy (DefaultRetryPolicy).
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(c *MCPPublisher) { c.retryPolicy = policy }
+}
+
+// NewMCPPublisher creates a new MCPPublisher
+func NewMCPPublisher(cfg *config.Config, opts ...Option) *MCPPublisher {
+	log := logrus.New()
+	log.SetLevel(logrus.InfoLevel)
+
+	c := &MCPPublisher{
 		cfg:     cfg,
 		baseURL: cfg.Xiaohongshu.ServerURL,
 		httpClient: &http.Client{
 			Timeout: 60 * time.Second,
 		},
+		log:         log,
+		retryPolicy: DefaultRetryPolicy(),
+	}
+	c.init()
+
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// redact returns s at debug level and a length-only placeholder otherwise,
+// so post titles/content never reach info-level (and therefore production)
+// logs.
+func (c *MCPPublisher) redact(s string) interface{} {
+	if c.log.IsLevelEnabled(logrus.DebugLevel) {
+		return s
+	}
+	return fmt.Sprintf("<redacted, %d bytes>", len(s))
+}
+
+// do runs httpReq and aborts as soon as ctx is done or the client's read
+// deadline (set via SetReadDeadline) passes - on top of, not instead of,
+// httpClient.Timeout, since publish_content drives a headless browser on
+// the server side and can hang well past what a fixed client timeout
+// should have to account for.
+func (c *MCPPublisher) do(ctx context.Context, httpReq *http.Request) (*http.Response, error) {
+	type result struct {
+		resp *http.Response
+		err  error
+	}
+
+	ch := make(chan result, 1)
+	go func() {
+		resp, err := c.httpClient.Do(httpReq)
+		ch <- result{resp, err}
+	}()
+
+	select {
+	case r := <-ch:
+		return r.resp, r.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-c.readCancel():
+		return nil, fmt.Errorf("xhs client: read deadline exceeded")
 	}
 }
 
@@ -120,7 +195,7 @@ type MCPError struct {
 }
 
 // PostContent posts content to Xiaohongshu
-func (c *Client) PostContent(req *PostRequest) (*PostResponse, error) {
+func (c *MCPPublisher) PostContent(ctx context.Context, req *PostRequest) (*PostResponse, error) {
 	// Create arguments map with headless parameter
 	arguments := map[string]interface{}{
 		"title":    req.Title,
@@ -129,10 +204,6 @@ func (c *Client) PostContent(req *PostRequest) (*PostResponse, error) {
 		"headless": c.cfg.Xiaohongshu.Headless,
 	}
 
-	// Debug: Log the arguments being sent
-	argumentsJSON, _ := json.MarshalIndent(arguments, "", "  ")
-	log.Printf("[DEBUG] PostContent arguments: %s", string(argumentsJSON))
-
 	mcpReq := MCPRequest{
 		JSONRPC: "2.0",
 		Method:  "tools/call",
@@ -143,11 +214,15 @@ func (c *Client) PostContent(req *PostRequest) (*PostResponse, error) {
 		ID: fmt.Sprintf("post_%d", time.Now().UnixNano()),
 	}
 
-	// Debug: Log the full MCP request
-	mcpReqJSON, _ := json.MarshalIndent(mcpReq, "", "  ")
-	log.Printf("[DEBUG] MCP Request: %s", string(mcpReqJSON))
+	c.log.WithFields(logrus.Fields{
+		"mcp_method": mcpReq.Method,
+		"mcp_id":     mcpReq.ID,
+		"title":      c.redact(req.Title),
+		"content":    c.redact(req.Content),
+		"images":     len(req.Images),
+	}).Debug("posting content")
 
-	mcpResp, err := c.callMCP(mcpReq)
+	mcpResp, err := c.callMCP(ctx, mcpReq)
 	if err != nil {
 		return nil, err
 	}
@@ -171,15 +246,28 @@ func (c *Client) PostContent(req *PostRequest) (*PostResponse, error) {
 	return &result, nil
 }
 
-// SchedulePost schedules a post for later publishing
-// Note: Scheduling is handled by the MCP server, this method posts immediately
-func (c *Client) SchedulePost(req *PostRequest, scheduleTime time.Time) (*PostResponse, error) {
-	// For now, just post immediately as the MCP server doesn't support scheduling
-	return c.PostContent(req)
+// Publish implements Publisher by posting content immediately.
+func (c *MCPPublisher) Publish(ctx context.Context, req *PostRequest) (*PostResponse, error) {
+	return c.PostContent(ctx, req)
+}
+
+// CheckLogin implements Publisher.
+func (c *MCPPublisher) CheckLogin(ctx context.Context) (*LoginStatusResponse, error) {
+	return c.CheckLoginStatus(ctx)
+}
+
+// SchedulePost posts immediately, ignoring scheduleTime.
+// Deprecated: for real scheduling (persisted across restarts, with status
+// tracking and a dispatcher that fires at scheduleTime) use
+// xiaohongshu-content/internal/schedule.Queue.Schedule, which dispatches to
+// any xhs.Publisher (MCPPublisher included). This method remains only as the
+// fire-and-forget fallback for callers that don't need that.
+func (c *MCPPublisher) SchedulePost(ctx context.Context, req *PostRequest, scheduleTime time.Time) (*PostResponse, error) {
+	return c.PostContent(ctx, req)
 }
 
 // GetPostStatus gets the status of a posted content
-func (c *Client) GetPostStatus(postID string) (*PostResponse, error) {
+func (c *MCPPublisher) GetPostStatus(ctx context.Context, postID string) (*PostResponse, error) {
 	mcpReq := MCPRequest{
 		Method: "get_post_status",
 		Params: map[string]string{"post_id": postID},
@@ -192,14 +280,14 @@ func (c *Client) GetPostStatus(postID string) (*PostResponse, error) {
 	}
 
 	apiURL := fmt.Sprintf("%s/mcp", c.baseURL)
-	httpReq, err := http.NewRequest("POST", apiURL, bytes.NewBuffer(reqBody))
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", apiURL, bytes.NewBuffer(reqBody))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create status request: %w", err)
 	}
 
 	httpReq.Header.Set("Content-Type", "application/json")
 
-	resp, err := c.httpClient.Do(httpReq)
+	resp, err := c.do(ctx, httpReq)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get post status: %w", err)
 	}
@@ -228,7 +316,7 @@ func (c *Client) GetPostStatus(postID string) (*PostResponse, error) {
 }
 
 // CheckLoginStatus checks the login status
-func (c *Client) CheckLoginStatus() (*LoginStatusResponse, error) {
+func (c *MCPPublisher) CheckLoginStatus(ctx context.Context) (*LoginStatusResponse, error) {
 	// Create arguments map with headless parameter
 	arguments := map[string]interface{}{
 		"headless": c.cfg.Xiaohongshu.Headless,
@@ -244,7 +332,7 @@ func (c *Client) CheckLoginStatus() (*LoginStatusResponse, error) {
 		ID: fmt.Sprintf("login_%d", time.Now().UnixNano()),
 	}
 
-	mcpResp, err := c.callMCP(mcpReq)
+	mcpResp, err := c.callMCP(ctx, mcpReq)
 	if err != nil {
 		return nil, err
 	}
@@ -264,7 +352,7 @@ func (c *Client) CheckLoginStatus() (*LoginStatusResponse, error) {
 }
 
 // ListFeeds gets the feeds list
-func (c *Client) ListFeeds() (*FeedsResponse, error) {
+func (c *MCPPublisher) ListFeeds(ctx context.Context) (*FeedsResponse, error) {
 	// Create arguments map with headless parameter
 	arguments := map[string]interface{}{
 		"headless": c.cfg.Xiaohongshu.Headless,
@@ -280,7 +368,7 @@ func (c *Client) ListFeeds() (*FeedsResponse, error) {
 		ID: fmt.Sprintf("feeds_%d", time.Now().UnixNano()),
 	}
 
-	mcpResp, err := c.callMCP(mcpReq)
+	mcpResp, err := c.callMCP(ctx, mcpReq)
 	if err != nil {
 		return nil, err
 	}
@@ -300,7 +388,7 @@ func (c *Client) ListFeeds() (*FeedsResponse, error) {
 }
 
 // SearchFeeds searches for content
-func (c *Client) SearchFeeds(keyword string) (*SearchResponse, error) {
+func (c *MCPPublisher) SearchFeeds(ctx context.Context, keyword string) (*SearchResponse, error) {
 	// Create arguments map with headless parameter
 	arguments := map[string]interface{}{
 		"keyword":  keyword,
@@ -317,7 +405,7 @@ func (c *Client) SearchFeeds(keyword string) (*SearchResponse, error) {
 		ID: fmt.Sprintf("search_%d", time.Now().UnixNano()),
 	}
 
-	mcpResp, err := c.callMCP(mcpReq)
+	mcpResp, err := c.callMCP(ctx, mcpReq)
 	if err != nil {
 		return nil, err
 	}
@@ -336,66 +424,76 @@ func (c *Client) SearchFeeds(keyword string) (*SearchResponse, error) {
 	return &result, nil
 }
 
-// callMCP is a helper method for making MCP calls
-func (c *Client) callMCP(mcpReq MCPRequest) (*MCPResponse, error) {
-	// Convert to JSON
-	reqBody, err := json.Marshal(mcpReq)
+// callMCP is a helper method for making MCP calls. It emits a single
+// structured summary log per call instead of the raw request/response JSON
+// dumps this used to produce, since those carried user post bodies straight
+// into production logs at an unbounded volume.
+func (c *MCPPublisher) callMCP(ctx context.Context, mcpReq MCPRequest) (*MCPResponse, error) {
+	start := time.Now()
+	fields := logrus.Fields{"mcp_method": mcpReq.Method, "mcp_id": mcpReq.ID}
+
+	mcpResp, statusCode, err := c.doCallMCP(ctx, mcpReq)
+	fields["duration_ms"] = time.Since(start).Milliseconds()
+	fields["status_code"] = statusCode
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal MCP request: %w", err)
+		fields["error"] = err.Error()
+		c.log.WithFields(fields).Error("MCP call failed")
+		return nil, err
+	}
+	if mcpResp.Error != nil {
+		callErr := &MCPCallError{Code: mcpResp.Error.Code, Message: mcpResp.Error.Message, Data: mcpResp.Error.Data}
+		fields["error"] = callErr.Error()
+		c.log.WithFields(fields).Warn("MCP call returned an error")
+		return nil, callErr
 	}
 
-	// Debug: Log the raw request body
-	log.Printf("[DEBUG] Raw MCP request body: %s", string(reqBody))
+	c.log.WithFields(fields).Info("MCP call succeeded")
+	return mcpResp, nil
+}
 
-	// Make HTTP request to MCP server
-	apiURL := fmt.Sprintf("%s/mcp", c.baseURL)
-	log.Printf("[DEBUG] Sending request to: %s", apiURL)
+// doCallMCP does the actual request/response round trip; callMCP wraps it
+// to keep the timing and logging in one place.
+func (c *MCPPublisher) doCallMCP(ctx context.Context, mcpReq MCPRequest) (*MCPResponse, int, error) {
+	reqBody, err := json.Marshal(mcpReq)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to marshal MCP request: %w", err)
+	}
 
-	httpReq, err := http.NewRequest("POST", apiURL, bytes.NewBuffer(reqBody))
+	apiURL := fmt.Sprintf("%s/mcp", c.baseURL)
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", apiURL, bytes.NewBuffer(reqBody))
 	if err != nil {
-		return nil, fmt.Errorf("failed to create HTTP request: %w", err)
+		return nil, 0, fmt.Errorf("failed to create HTTP request: %w", err)
 	}
 
 	httpReq.Header.Set("Content-Type", "application/json")
 
-	resp, err := c.httpClient.Do(httpReq)
+	resp, err := c.do(ctx, httpReq)
 	if err != nil {
-		return nil, fmt.Errorf("failed to call Xiaohongshu MCP server: %w", err)
+		return nil, 0, &TransportError{Err: err}
 	}
 	defer resp.Body.Close()
 
-	log.Printf("[DEBUG] MCP server response status: %d", resp.StatusCode)
-
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("Xiaohongshu MCP server returned status %d", resp.StatusCode)
+		return nil, resp.StatusCode, &HTTPStatusError{StatusCode: resp.StatusCode}
 	}
 
-	// Parse MCP response
 	var mcpResp MCPResponse
 	if err := json.NewDecoder(resp.Body).Decode(&mcpResp); err != nil {
-		return nil, fmt.Errorf("failed to decode MCP response: %w", err)
-	}
-
-	// Debug: Log the MCP response
-	mcpRespJSON, _ := json.MarshalIndent(mcpResp, "", "  ")
-	log.Printf("[DEBUG] MCP Response: %s", string(mcpRespJSON))
-
-	if mcpResp.Error != nil {
-		return nil, fmt.Errorf("MCP error %d: %s", mcpResp.Error.Code, mcpResp.Error.Message)
+		return nil, resp.StatusCode, fmt.Errorf("failed to decode MCP response: %w", err)
 	}
 
-	return &mcpResp, nil
+	return &mcpResp, resp.StatusCode, nil
 }
 
 // TestConnection tests the connection to Xiaohongshu MCP server
-func (c *Client) TestConnection() error {
+func (c *MCPPublisher) TestConnection(ctx context.Context) error {
 	// Test connection by checking login status
-	_, err := c.CheckLoginStatus()
+	_, err := c.CheckLoginStatus(ctx)
 	return err
 }
 
 // GetAccountInfo gets account information
-func (c *Client) GetAccountInfo() (map[string]interface{}, error) {
+func (c *MCPPublisher) GetAccountInfo(ctx context.Context) (map[string]interface{}, error) {
 	mcpReq := MCPRequest{
 		Method: "get_account_info",
 		Params: nil,
@@ -408,14 +506,14 @@ func (c *Client) GetAccountInfo() (map[string]interface{}, error) {
 	}
 
 	apiURL := fmt.Sprintf("%s/mcp", c.baseURL)
-	httpReq, err := http.NewRequest("POST", apiURL, bytes.NewBuffer(reqBody))
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", apiURL, bytes.NewBuffer(reqBody))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create account request: %w", err)
 	}
 
 	httpReq.Header.Set("Content-Type", "application/json")
 
-	resp, err := c.httpClient.Do(httpReq)
+	resp, err := c.do(ctx, httpReq)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get account info: %w", err)
 	}
@@ -437,28 +535,8 @@ func (c *Client) GetAccountInfo() (map[string]interface{}, error) {
 	return nil, fmt.Errorf("invalid account info response format")
 }
 
-// PostWithRetry posts content with retry logic
-func (c *Client) PostWithRetry(req *PostRequest, maxRetries int) (*PostResponse, error) {
-	var lastErr error
-
-	for i := 0; i < maxRetries; i++ {
-		resp, err := c.PostContent(req)
-		if err == nil {
-			return resp, nil
-		}
-
-		lastErr = err
-		if i < maxRetries-1 {
-			// Wait before retry
-			time.Sleep(time.Duration(i+1) * 10 * time.Second)
-		}
-	}
-
-	return nil, fmt.Errorf("failed to post content after %d retries: %w", maxRetries, lastErr)
-}
-
 // ValidatePostRequest validates a post request
-func (c *Client) ValidatePostRequest(req *PostRequest) error {
+func (c *MCPPublisher) ValidatePostRequest(req *PostRequest) error {
 	if req.Title == "" {
 		return fmt.Errorf("title is required")
 	}
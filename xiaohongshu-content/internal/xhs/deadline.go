@@ -0,0 +1,75 @@
+package xhs
+
+import (
+	"sync"
+	"time"
+)
+
+// deadlineTimer gives *MCPPublisher SetReadDeadline/SetWriteDeadline, mirroring
+// net.Conn's deadline API: a caller sets an absolute time once and every
+// in-flight (and future) operation aborts when it passes, without needing
+// to thread a context.Context down for that specific purpose. Modeled on
+// the shared-deadline pattern used by netstack's gonet adapter - two cancel
+// channels, closed either by a time.AfterFunc at the deadline or by
+// whoever resets the deadline, with a fresh channel allocated on reset so
+// a past close doesn't leak into the next deadline's waiters.
+type deadlineTimer struct {
+	mu sync.Mutex
+
+	readTimer    *time.Timer
+	readCancelCh chan struct{}
+
+	writeTimer    *time.Timer
+	writeCancelCh chan struct{}
+}
+
+func (d *deadlineTimer) init() {
+	d.readCancelCh = make(chan struct{})
+	d.writeCancelCh = make(chan struct{})
+}
+
+// readCancel returns the channel that closes when the read deadline passes.
+func (d *deadlineTimer) readCancel() <-chan struct{} {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.readCancelCh
+}
+
+// writeCancel returns the channel that closes when the write deadline passes.
+func (d *deadlineTimer) writeCancel() <-chan struct{} {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.writeCancelCh
+}
+
+func (d *deadlineTimer) setDeadline(timer **time.Timer, cancelCh *chan struct{}, t time.Time) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if *timer != nil && !(*timer).Stop() {
+		// The old timer already fired and closed the old channel; allocate
+		// a fresh one so new waiters don't see a deadline that's already
+		// expired from a previous call.
+		*cancelCh = make(chan struct{})
+	}
+
+	if t.IsZero() {
+		return
+	}
+
+	ch := *cancelCh
+	*timer = time.AfterFunc(time.Until(t), func() { close(ch) })
+}
+
+// SetReadDeadline bounds the time any read-side wait (receiving an MCP
+// response) is allowed to take. Passing the zero Time clears it.
+func (d *deadlineTimer) SetReadDeadline(t time.Time) {
+	d.setDeadline(&d.readTimer, &d.readCancelCh, t)
+}
+
+// SetWriteDeadline sets the write-side deadline. Passing the zero Time
+// clears it. No MCPPublisher call waits on it (do selects only on the read
+// deadline), so it does not bound any request.
+func (d *deadlineTimer) SetWriteDeadline(t time.Time) {
+	d.setDeadline(&d.writeTimer, &d.writeCancelCh, t)
+}
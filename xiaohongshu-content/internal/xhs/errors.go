@@ -0,0 +1,46 @@
+package xhs
+
+import "fmt"
+
+// MCP JSON-RPC error codes, in the reserved -32000..-32099 server-error
+// range, that this client treats as a transient failure worth retrying.
+// The MCP servers in this repo do not use them with these meanings:
+// xiaohongshu-cover-mcp's mcp package uses -32001 for rate limiting and
+// -32002 for cancellation, and xiaohongshu-unified-mcp reports rate limiting
+// as -32000.
+const (
+	MCPErrCodeBrowserBusy = -32001
+	MCPErrCodeRateLimited = -32002
+)
+
+// TransportError wraps a failure reaching the MCP server at all (DNS,
+// connection refused, timed out before any response) - always retryable.
+type TransportError struct{ Err error }
+
+func (e *TransportError) Error() string { return fmt.Sprintf("transport error: %v", e.Err) }
+func (e *TransportError) Unwrap() error { return e.Err }
+
+// HTTPStatusError is a non-200 HTTP response from the MCP server, below the
+// JSON-RPC layer.
+type HTTPStatusError struct{ StatusCode int }
+
+func (e *HTTPStatusError) Error() string {
+	return fmt.Sprintf("MCP server returned status %d", e.StatusCode)
+}
+
+// MCPCallError is a JSON-RPC level error returned by the MCP server.
+type MCPCallError struct {
+	Code    int
+	Message string
+	Data    interface{}
+}
+
+func (e *MCPCallError) Error() string { return fmt.Sprintf("MCP error %d: %s", e.Code, e.Message) }
+
+// ValidationError wraps a ValidatePostRequest failure. It is never
+// retryable: retrying an invalid request burns attempts on something that
+// will never succeed.
+type ValidationError struct{ Err error }
+
+func (e *ValidationError) Error() string { return e.Err.Error() }
+func (e *ValidationError) Unwrap() error { return e.Err }
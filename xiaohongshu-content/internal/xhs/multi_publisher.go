@@ -0,0 +1,91 @@
+package xhs
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// MultiPublisher fans a single publish out to several backends concurrently
+// and aggregates their responses under one request ID, so a single
+// POST /api/generate-and-publish call can cross-post the generated cover to
+// multiple platforms at once. Unlike the weather package's MultiProvider
+// (which races providers and keeps the first success), MultiPublisher runs
+// every backend and reports all of their outcomes.
+type MultiPublisher struct {
+	backends []Publisher
+}
+
+// NewMultiPublisher creates a MultiPublisher that fans out to backends, in
+// order. The first backend is treated as primary for CheckLogin/ListFeeds/
+// SearchFeeds, which don't have a natural way to aggregate across backends.
+func NewMultiPublisher(backends ...Publisher) *MultiPublisher {
+	return &MultiPublisher{backends: backends}
+}
+
+// Publish calls Publish on every backend concurrently and aggregates the
+// results. The returned PostResponse's Results field holds one entry per
+// backend, in the same order as backends; the top-level fields mirror the
+// first successful result, or report failure if every backend failed.
+func (m *MultiPublisher) Publish(ctx context.Context, req *PostRequest) (*PostResponse, error) {
+	if len(m.backends) == 0 {
+		return nil, fmt.Errorf("multi publisher: no backends configured")
+	}
+
+	results := make([]PostResponse, len(m.backends))
+	var wg sync.WaitGroup
+	for i, backend := range m.backends {
+		i, backend := i, backend
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := backend.Publish(ctx, req)
+			if err != nil {
+				results[i] = PostResponse{Status: "failed", Error: err.Error()}
+				return
+			}
+			results[i] = *resp
+		}()
+	}
+	wg.Wait()
+
+	aggregate := PostResponse{PostedAt: time.Now(), Results: results}
+	for _, r := range results {
+		if r.Error == "" {
+			aggregate.PostID = r.PostID
+			aggregate.URL = r.URL
+			aggregate.Status = "posted"
+			aggregate.Message = "published to one or more backends"
+			return &aggregate, nil
+		}
+	}
+
+	aggregate.Status = "failed"
+	aggregate.Error = "all backends failed"
+	return &aggregate, fmt.Errorf("multi publisher: all %d backends failed", len(m.backends))
+}
+
+// CheckLogin delegates to the first configured backend.
+func (m *MultiPublisher) CheckLogin(ctx context.Context) (*LoginStatusResponse, error) {
+	if len(m.backends) == 0 {
+		return nil, fmt.Errorf("multi publisher: no backends configured")
+	}
+	return m.backends[0].CheckLogin(ctx)
+}
+
+// ListFeeds delegates to the first configured backend.
+func (m *MultiPublisher) ListFeeds(ctx context.Context) (*FeedsResponse, error) {
+	if len(m.backends) == 0 {
+		return nil, fmt.Errorf("multi publisher: no backends configured")
+	}
+	return m.backends[0].ListFeeds(ctx)
+}
+
+// SearchFeeds delegates to the first configured backend.
+func (m *MultiPublisher) SearchFeeds(ctx context.Context, keyword string) (*SearchResponse, error) {
+	if len(m.backends) == 0 {
+		return nil, fmt.Errorf("multi publisher: no backends configured")
+	}
+	return m.backends[0].SearchFeeds(ctx, keyword)
+}
@@ -0,0 +1,54 @@
+package xhs
+
+import (
+	"context"
+	"time"
+)
+
+// Publisher is the destination-agnostic half of MCPPublisher's API: anything
+// that can publish a post, check login state, and list/search feeds. It lets
+// callers (schedule.Queue, the orchestrator, UnifiedServer) be built against
+// any registered backend - the MCP server, another platform, a composite of
+// several, or a dry-run stub for tests - instead of *MCPPublisher directly.
+type Publisher interface {
+	Publish(ctx context.Context, req *PostRequest) (*PostResponse, error)
+	CheckLogin(ctx context.Context) (*LoginStatusResponse, error)
+	ListFeeds(ctx context.Context) (*FeedsResponse, error)
+	SearchFeeds(ctx context.Context, keyword string) (*SearchResponse, error)
+}
+
+// DryRunPublisher implements Publisher without touching any real backend -
+// every call succeeds immediately with a synthetic response. Useful for
+// exercising the orchestrator and schedule.Queue in tests without a running
+// MCP server.
+type DryRunPublisher struct{}
+
+// NewDryRunPublisher creates a DryRunPublisher.
+func NewDryRunPublisher() *DryRunPublisher {
+	return &DryRunPublisher{}
+}
+
+// Publish always succeeds, returning a synthetic post ID.
+func (d *DryRunPublisher) Publish(ctx context.Context, req *PostRequest) (*PostResponse, error) {
+	return &PostResponse{
+		PostID:   "dryrun_" + time.Now().Format("20060102150405.000000"),
+		Status:   "posted",
+		Message:  "dry run: not actually published",
+		PostedAt: time.Now(),
+	}, nil
+}
+
+// CheckLogin always reports a logged-in state.
+func (d *DryRunPublisher) CheckLogin(ctx context.Context) (*LoginStatusResponse, error) {
+	return &LoginStatusResponse{LoggedIn: true, Message: "dry run"}, nil
+}
+
+// ListFeeds always returns an empty feed list.
+func (d *DryRunPublisher) ListFeeds(ctx context.Context) (*FeedsResponse, error) {
+	return &FeedsResponse{Message: "dry run"}, nil
+}
+
+// SearchFeeds always returns no results.
+func (d *DryRunPublisher) SearchFeeds(ctx context.Context, keyword string) (*SearchResponse, error) {
+	return &SearchResponse{Message: "dry run"}, nil
+}
@@ -0,0 +1,151 @@
+package xhs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"time"
+)
+
+// RetryPolicy tunes PostWithRetry's backoff and error classification. The
+// zero value is not usable directly - start from DefaultRetryPolicy.
+type RetryPolicy struct {
+	MaxAttempts int
+	Base        time.Duration
+	Cap         time.Duration
+
+	// RetryableFunc decides whether err is worth another attempt. Defaults
+	// to IsRetryable.
+	RetryableFunc func(err error) bool
+}
+
+// DefaultRetryPolicy retries transport errors, 5xx responses, and MCP
+// errors that indicate a transient condition (browser busy, rate limited),
+// using exponential backoff with full jitter capped at 60s.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:   5,
+		Base:          2 * time.Second,
+		Cap:           60 * time.Second,
+		RetryableFunc: IsRetryable,
+	}
+}
+
+// IsRetryable classifies err by type: validation errors never retry,
+// transport errors and 5xx responses always do, and MCP-level errors retry
+// only for codes that indicate a transient failure.
+func IsRetryable(err error) bool {
+	var validationErr *ValidationError
+	if errors.As(err, &validationErr) {
+		return false
+	}
+
+	var transportErr *TransportError
+	if errors.As(err, &transportErr) {
+		return true
+	}
+
+	var httpErr *HTTPStatusError
+	if errors.As(err, &httpErr) {
+		return httpErr.StatusCode >= 500
+	}
+
+	var mcpErr *MCPCallError
+	if errors.As(err, &mcpErr) {
+		switch mcpErr.Code {
+		case MCPErrCodeBrowserBusy, MCPErrCodeRateLimited:
+			return true
+		default:
+			return false
+		}
+	}
+
+	return false
+}
+
+// retryAfter extracts a server-supplied retry delay from an MCPCallError's
+// Data field, if present, supporting either a "retry_after_ms" or
+// "retry_after_seconds" number.
+func retryAfter(err error) (time.Duration, bool) {
+	var mcpErr *MCPCallError
+	if !errors.As(err, &mcpErr) || mcpErr.Data == nil {
+		return 0, false
+	}
+
+	data, ok := mcpErr.Data.(map[string]interface{})
+	if !ok {
+		return 0, false
+	}
+
+	if ms, ok := data["retry_after_ms"].(float64); ok {
+		return time.Duration(ms) * time.Millisecond, true
+	}
+	if s, ok := data["retry_after_seconds"].(float64); ok {
+		return time.Duration(s * float64(time.Second)), true
+	}
+	return 0, false
+}
+
+// backoffWithFullJitter returns a random delay in [0, min(cap, base*2^attempt)),
+// per https://aws.amazon.com/blogs/architecture/exponential-backoff-and-jitter/.
+func backoffWithFullJitter(base, cap time.Duration, attempt int) time.Duration {
+	upper := float64(base) * math.Pow(2, float64(attempt))
+	if upper > float64(cap) {
+		upper = float64(cap)
+	}
+	return time.Duration(rand.Float64() * upper)
+}
+
+// PostWithRetry posts content, retrying only errors the policy classifies
+// as transient, with exponential backoff and full jitter between attempts.
+// maxRetries, if greater than zero, overrides the policy's MaxAttempts for
+// this call. It honors ctx's deadline, so total retry time is bounded by
+// whichever is shorter: ctx or the policy's own attempt budget.
+func (c *MCPPublisher) PostWithRetry(ctx context.Context, req *PostRequest, maxRetries int) (*PostResponse, error) {
+	if err := c.ValidatePostRequest(req); err != nil {
+		return nil, &ValidationError{Err: err}
+	}
+
+	policy := c.retryPolicy
+	if maxRetries > 0 {
+		policy.MaxAttempts = maxRetries
+	}
+	if policy.RetryableFunc == nil {
+		policy.RetryableFunc = IsRetryable
+	}
+
+	var lastErr error
+	for attempt := 0; attempt < policy.MaxAttempts; attempt++ {
+		if err := ctx.Err(); err != nil {
+			return nil, fmt.Errorf("post retry loop cancelled: %w", err)
+		}
+
+		resp, err := c.PostContent(ctx, req)
+		if err == nil {
+			return resp, nil
+		}
+
+		lastErr = err
+		if !policy.RetryableFunc(err) {
+			return nil, fmt.Errorf("post failed with a non-retryable error: %w", err)
+		}
+		if attempt == policy.MaxAttempts-1 {
+			break
+		}
+
+		delay := backoffWithFullJitter(policy.Base, policy.Cap, attempt)
+		if hint, ok := retryAfter(err); ok {
+			delay = hint
+		}
+
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return nil, fmt.Errorf("post retry loop cancelled during backoff: %w", ctx.Err())
+		}
+	}
+
+	return nil, fmt.Errorf("failed to post content after %d attempts: %w", policy.MaxAttempts, lastErr)
+}
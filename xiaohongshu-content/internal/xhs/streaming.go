@@ -0,0 +1,243 @@
+package xhs
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// callMCPBatch packs reqs into a single JSON-RPC 2.0 batch request (a JSON
+// array of requests, per the batch spec) instead of one HTTP round trip per
+// call - useful when the orchestrator needs to check login, list feeds, and
+// post in sequence. Responses are demultiplexed by ID and returned in the
+// same order as reqs, regardless of what order the server replied in.
+func (c *MCPPublisher) callMCPBatch(ctx context.Context, reqs []MCPRequest) ([]MCPResponse, error) {
+	if len(reqs) == 0 {
+		return nil, nil
+	}
+
+	reqBody, err := json.Marshal(reqs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal MCP batch request: %w", err)
+	}
+
+	apiURL := fmt.Sprintf("%s/mcp", c.baseURL)
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", apiURL, bytes.NewBuffer(reqBody))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.do(ctx, httpReq)
+	if err != nil {
+		return nil, &TransportError{Err: err}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, &HTTPStatusError{StatusCode: resp.StatusCode}
+	}
+
+	var raw []MCPResponse
+	if err := json.NewDecoder(resp.Body).Decode(&raw); err != nil {
+		return nil, fmt.Errorf("failed to decode MCP batch response: %w", err)
+	}
+
+	byID := make(map[string]MCPResponse, len(raw))
+	for _, r := range raw {
+		byID[r.ID] = r
+	}
+
+	ordered := make([]MCPResponse, len(reqs))
+	for i, req := range reqs {
+		r, ok := byID[req.ID]
+		if !ok {
+			return nil, fmt.Errorf("callMCPBatch: no response for request id %q", req.ID)
+		}
+		ordered[i] = r
+	}
+	return ordered, nil
+}
+
+// PostProgress is one progress update from PostContentStream.
+type PostProgress struct {
+	Stage    string        `json:"stage,omitempty"`
+	Message  string        `json:"message"`
+	Done     bool          `json:"done"`
+	Response *PostResponse `json:"response,omitempty"`
+	Err      string        `json:"error,omitempty"`
+}
+
+// progressEvent is one line of a streamed publish_content reply, whether it
+// arrives as an SSE "data:" line or an NDJSON line.
+type progressEvent struct {
+	Stage   string          `json:"stage"`
+	Message string          `json:"message"`
+	Done    bool            `json:"done"`
+	Result  json.RawMessage `json:"result"`
+	Error   *MCPError       `json:"error"`
+}
+
+// PostContentStream posts req and returns a channel of progressive status
+// updates ("uploading image 2/9", "waiting for captcha") for MCP servers
+// that reply with text/event-stream or chunked NDJSON instead of a single
+// terminal JSON-RPC response, which publish_content can take minutes to
+// send for a browser-driven publish. The channel is closed once a Done
+// update has been sent. Servers that reply with a single JSON object still
+// work: the channel receives exactly one, already-Done update.
+func (c *MCPPublisher) PostContentStream(ctx context.Context, req *PostRequest) (<-chan PostProgress, error) {
+	arguments := map[string]interface{}{
+		"title":    req.Title,
+		"content":  req.Content,
+		"images":   req.Images,
+		"headless": c.cfg.Xiaohongshu.Headless,
+		"stream":   true,
+	}
+
+	mcpReq := MCPRequest{
+		JSONRPC: "2.0",
+		Method:  "tools/call",
+		Params: map[string]interface{}{
+			"name":      "publish_content",
+			"arguments": arguments,
+		},
+		ID: fmt.Sprintf("post_stream_%d", time.Now().UnixNano()),
+	}
+
+	reqBody, err := json.Marshal(mcpReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal MCP request: %w", err)
+	}
+
+	apiURL := fmt.Sprintf("%s/mcp", c.baseURL)
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", apiURL, bytes.NewBuffer(reqBody))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Accept", "text/event-stream, application/x-ndjson")
+
+	resp, err := c.do(ctx, httpReq)
+	if err != nil {
+		return nil, &TransportError{Err: err}
+	}
+
+	progress := make(chan PostProgress, 8)
+	go func() {
+		defer resp.Body.Close()
+		defer close(progress)
+
+		if resp.StatusCode != http.StatusOK {
+			progress <- PostProgress{Done: true, Err: (&HTTPStatusError{StatusCode: resp.StatusCode}).Error()}
+			return
+		}
+
+		switch ct := resp.Header.Get("Content-Type"); {
+		case strings.HasPrefix(ct, "text/event-stream"):
+			c.streamSSE(resp.Body, progress)
+		case strings.HasPrefix(ct, "application/x-ndjson"):
+			c.streamNDJSON(resp.Body, progress)
+		default:
+			c.streamSingle(resp.Body, progress)
+		}
+	}()
+
+	return progress, nil
+}
+
+// streamSSE reads "data: {...}" lines from an SSE body and emits one
+// progress update per event.
+func (c *MCPPublisher) streamSSE(body io.Reader, progress chan<- PostProgress) {
+	scanner := bufio.NewScanner(body)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		data, ok := strings.CutPrefix(line, "data:")
+		if !ok {
+			continue
+		}
+		data = strings.TrimSpace(data)
+		if data == "" {
+			continue
+		}
+		if done := c.emitProgress([]byte(data), progress); done {
+			return
+		}
+	}
+}
+
+// streamNDJSON reads one JSON object per line and emits one progress update
+// per line.
+func (c *MCPPublisher) streamNDJSON(body io.Reader, progress chan<- PostProgress) {
+	scanner := bufio.NewScanner(body)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		if done := c.emitProgress(line, progress); done {
+			return
+		}
+	}
+}
+
+// streamSingle handles a server that replies with one terminal JSON-RPC
+// response instead of a stream, so PostContentStream works against either.
+func (c *MCPPublisher) streamSingle(body io.Reader, progress chan<- PostProgress) {
+	var mcpResp MCPResponse
+	if err := json.NewDecoder(body).Decode(&mcpResp); err != nil {
+		progress <- PostProgress{Done: true, Err: fmt.Sprintf("failed to decode response: %v", err)}
+		return
+	}
+
+	if mcpResp.Error != nil {
+		progress <- PostProgress{Done: true, Err: (&MCPCallError{Code: mcpResp.Error.Code, Message: mcpResp.Error.Message, Data: mcpResp.Error.Data}).Error()}
+		return
+	}
+
+	resultBytes, err := json.Marshal(mcpResp.Result)
+	if err != nil {
+		progress <- PostProgress{Done: true, Err: fmt.Sprintf("failed to marshal result: %v", err)}
+		return
+	}
+	c.emitProgress(mustWrapDone(resultBytes), progress)
+}
+
+// mustWrapDone wraps a final result payload as a progressEvent JSON blob so
+// streamSingle can share emitProgress's decoding path.
+func mustWrapDone(result json.RawMessage) []byte {
+	data, _ := json.Marshal(progressEvent{Done: true, Result: result})
+	return data
+}
+
+// emitProgress decodes one progress line and sends it on progress, reporting
+// whether this was the final (Done) update.
+func (c *MCPPublisher) emitProgress(data []byte, progress chan<- PostProgress) bool {
+	var evt progressEvent
+	if err := json.Unmarshal(data, &evt); err != nil {
+		progress <- PostProgress{Message: fmt.Sprintf("malformed progress event: %v", err)}
+		return false
+	}
+
+	if evt.Error != nil {
+		progress <- PostProgress{Stage: evt.Stage, Done: true, Err: (&MCPCallError{Code: evt.Error.Code, Message: evt.Error.Message, Data: evt.Error.Data}).Error()}
+		return true
+	}
+
+	update := PostProgress{Stage: evt.Stage, Message: evt.Message, Done: evt.Done}
+	if evt.Done && len(evt.Result) > 0 {
+		var result PostResponse
+		if err := json.Unmarshal(evt.Result, &result); err == nil {
+			result.PostedAt = time.Now()
+			update.Response = &result
+		}
+	}
+
+	progress <- update
+	return update.Done
+}
@@ -1,23 +1,66 @@
 package main
 
 import (
+	"context"
+	"flag"
 	"fmt"
+	"io"
 	"log"
+	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/gin-gonic/gin"
 
 	"xiaohongshu-content/internal/config"
 	"xiaohongshu-content/internal/orchestrator"
+	"xiaohongshu-content/internal/schedule"
+	"xiaohongshu-content/internal/xhs"
 )
 
 func main() {
+	serve := flag.Bool("serve", false, "Run the schedule API server instead of a one-shot workflow run")
+	port := flag.String("port", ":18063", "Port for the schedule API server (only used with -serve)")
+	dbPath := flag.String("schedule-db", "scheduled_posts.db", "Path to the BoltDB file backing the post schedule")
+	configPath := flag.String("config", "", "Path to config.json (overrides CONFIG_FILE env; defaults to ./config.json)")
+	flag.Parse()
+
 	fmt.Println("Starting Xiaohongshu Content Generator...")
 
 	// Load configuration
-	cfg, err := config.Load()
+	cfg, err := config.LoadFile(config.ResolvePath(*configPath))
 	if err != nil {
 		log.Fatalf("Failed to load configuration: %v", err)
 	}
 
+	client := xhs.NewMCPPublisher(cfg)
+	queue, err := schedule.Open(*dbPath, client)
+	if err != nil {
+		log.Fatalf("Failed to open schedule store: %v", err)
+	}
+	defer queue.Close()
+
+	if *serve {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go queue.Start(ctx)
+
+		server := NewUnifiedServer(queue, client)
+		go func() {
+			if err := server.Start(*port); err != nil && err != http.ErrServerClosed {
+				log.Fatalf("Failed to start schedule API server: %v", err)
+			}
+		}()
+
+		sigChan := make(chan os.Signal, 1)
+		signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+		<-sigChan
+		fmt.Println("Shutting down schedule API server...")
+		return
+	}
+
 	// Create orchestrator
 	orch := orchestrator.New(cfg)
 
@@ -28,4 +71,114 @@ func main() {
 
 	fmt.Println("Content generation and posting completed successfully!")
 	os.Exit(0)
+}
+
+// UnifiedServer exposes the post schedule over REST: POST /api/schedule to
+// queue a post, GET /api/schedule to list jobs, DELETE /api/schedule/:id to
+// cancel a still-pending one. It also exposes POST /api/publish/stream,
+// which publishes immediately and streams progress back over SSE instead
+// of blocking until publish_content finishes.
+type UnifiedServer struct {
+	queue     *schedule.Queue
+	publisher *xhs.MCPPublisher
+	router    *gin.Engine
+}
+
+// NewUnifiedServer creates a schedule API server around queue, using
+// publisher for the immediate streaming-publish endpoint.
+func NewUnifiedServer(queue *schedule.Queue, publisher *xhs.MCPPublisher) *UnifiedServer {
+	return &UnifiedServer{queue: queue, publisher: publisher}
+}
+
+// Start sets up routes and serves them on port until it errors out.
+func (s *UnifiedServer) Start(port string) error {
+	s.setupRoutes()
+	return s.router.Run(port)
+}
+
+// setupRoutes wires up the schedule REST endpoints.
+func (s *UnifiedServer) setupRoutes() {
+	s.router = gin.Default()
+
+	api := s.router.Group("/api")
+	{
+		api.POST("/schedule", s.handleCreateSchedule)
+		api.GET("/schedule", s.handleListSchedule)
+		api.DELETE("/schedule/:id", s.handleCancelSchedule)
+		api.POST("/publish/stream", s.handlePublishStream)
+	}
+
+	s.router.GET("/health", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"status": "ok"})
+	})
+}
+
+// scheduleRequest is the POST /api/schedule body: a PostRequest plus when
+// and in which timezone to fire it.
+type scheduleRequest struct {
+	Post         xhs.PostRequest `json:"post"`
+	ScheduleTime time.Time       `json:"schedule_time"`
+	TZ           string          `json:"tz"`
+}
+
+func (s *UnifiedServer) handleCreateSchedule(c *gin.Context) {
+	var req scheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.TZ == "" {
+		req.TZ = "Asia/Shanghai"
+	}
+
+	job, err := s.queue.Schedule(&req.Post, req.ScheduleTime, req.TZ)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, job)
+}
+
+func (s *UnifiedServer) handleListSchedule(c *gin.Context) {
+	jobs, err := s.queue.List()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"jobs": jobs})
+}
+
+func (s *UnifiedServer) handleCancelSchedule(c *gin.Context) {
+	if err := s.queue.Cancel(c.Param("id")); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "cancelled"})
+}
+
+// handlePublishStream publishes a post immediately and streams
+// xhs.PostProgress updates back as Server-Sent Events, so a client sees
+// "uploading image 2/9", "waiting for captcha", etc. instead of a single
+// reply after publish_content finishes minutes later.
+func (s *UnifiedServer) handlePublishStream(c *gin.Context) {
+	var req xhs.PostRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	progress, err := s.publisher.PostContentStream(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Stream(func(w io.Writer) bool {
+		update, ok := <-progress
+		if !ok {
+			return false
+		}
+		c.SSEvent("progress", update)
+		return !update.Done
+	})
 }
\ No newline at end of file
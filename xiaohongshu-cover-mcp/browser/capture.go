@@ -0,0 +1,216 @@
+package browser
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/chromedp/cdproto/emulation"
+	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/cdproto/page"
+	"github.com/chromedp/chromedp"
+)
+
+// Viewport overrides the tab's rendering viewport before a screenshot is
+// taken, mirroring Chrome DevTools Protocol's device emulation.
+type Viewport struct {
+	W                 int
+	H                 int
+	DeviceScaleFactor float64
+	Mobile            bool
+}
+
+// Clip restricts the screenshot to a sub-rectangle of the page, in CSS
+// pixels relative to the top-left of the viewport/document.
+type Clip struct {
+	X, Y, W, H float64
+}
+
+// WaitCondType names one readiness check TakeScreenshot can wait on before
+// capturing, replacing the old blunt Sleep(WaitTime).
+type WaitCondType string
+
+const (
+	// WaitSelector waits for Selector to become visible.
+	WaitSelector WaitCondType = "selector"
+	// WaitNetworkIdle waits until no network connections have been active
+	// for 500ms.
+	WaitNetworkIdle WaitCondType = "network-idle"
+	// WaitJS polls Expr until it evaluates truthy.
+	WaitJS WaitCondType = "js"
+	// WaitTimeout just sleeps for Timeout, for pages with no reliable
+	// readiness signal.
+	WaitTimeout WaitCondType = "timeout"
+)
+
+// WaitCond is a single readiness check to run before capturing.
+type WaitCond struct {
+	Type     WaitCondType
+	Selector string        // WaitSelector
+	Expr     string        // WaitJS
+	Timeout  time.Duration // WaitTimeout, and the poll/network-idle deadline
+}
+
+// action builds the chromedp.Action implementing this WaitCond.
+func (c WaitCond) action() (chromedp.Action, error) {
+	switch c.Type {
+	case WaitSelector:
+		if c.Selector == "" {
+			return nil, fmt.Errorf("WaitSelector condition requires Selector")
+		}
+		return chromedp.WaitVisible(c.Selector, chromedp.BySearch), nil
+	case WaitNetworkIdle:
+		return waitNetworkIdle(pollTimeout(c.Timeout)), nil
+	case WaitJS:
+		if c.Expr == "" {
+			return nil, fmt.Errorf("WaitJS condition requires Expr")
+		}
+		return chromedp.Poll(c.Expr, nil, chromedp.WithPollingTimeout(pollTimeout(c.Timeout))), nil
+	case WaitTimeout:
+		timeout := c.Timeout
+		if timeout == 0 {
+			timeout = 3 * time.Second
+		}
+		return chromedp.Sleep(timeout), nil
+	default:
+		return nil, fmt.Errorf("unknown wait condition type %q", c.Type)
+	}
+}
+
+// waitNetworkIdle blocks until no network request has been in flight for
+// 500ms, or timeout elapses - whichever comes first.
+func waitNetworkIdle(timeout time.Duration) chromedp.Action {
+	return chromedp.ActionFunc(func(ctx context.Context) error {
+		var inFlight int32
+		idleSince := time.Now()
+
+		chromedp.ListenTarget(ctx, func(ev interface{}) {
+			switch ev.(type) {
+			case *network.EventRequestWillBeSent:
+				atomic.AddInt32(&inFlight, 1)
+			case *network.EventLoadingFinished, *network.EventLoadingFailed:
+				if atomic.AddInt32(&inFlight, -1) <= 0 {
+					idleSince = time.Now()
+				}
+			}
+		})
+
+		deadline := time.Now().Add(timeout)
+		for time.Now().Before(deadline) {
+			if atomic.LoadInt32(&inFlight) <= 0 && time.Since(idleSince) >= 500*time.Millisecond {
+				return nil
+			}
+			time.Sleep(50 * time.Millisecond)
+		}
+		return nil // best-effort: capture anyway once the deadline is hit
+	})
+}
+
+func pollTimeout(d time.Duration) time.Duration {
+	if d == 0 {
+		return 10 * time.Second
+	}
+	return d
+}
+
+// captureFormat maps Request.Format to the CDP screenshot format, defaulting
+// to PNG.
+func captureFormat(format string) page.CaptureScreenshotParamsFormat {
+	switch format {
+	case "jpeg":
+		return page.CaptureScreenshotParamsFormatJpeg
+	case "webp":
+		return page.CaptureScreenshotParamsFormatWebp
+	default:
+		return page.CaptureScreenshotParamsFormatPng
+	}
+}
+
+// capture runs req's navigation, viewport/wait setup, and screenshot
+// capture against ctx (a borrowed tab), returning the encoded image bytes.
+func capture(ctx context.Context, req *Request) ([]byte, error) {
+	actions := []chromedp.Action{chromedp.Navigate(req.URL)}
+
+	if req.Viewport != nil {
+		v := req.Viewport
+		scale := v.DeviceScaleFactor
+		if scale == 0 {
+			scale = 1
+		}
+		actions = append(actions, chromedp.EmulateViewport(int64(v.W), int64(v.H),
+			chromedp.EmulateScale(scale),
+			chromedp.EmulateMobile(v.Mobile, false),
+		))
+	}
+
+	waitConds := req.WaitFor
+	if len(waitConds) == 0 {
+		// Preserve the old default behaviour: wait for Selector to appear,
+		// then sleep WaitTime seconds.
+		waitConds = []WaitCond{
+			{Type: WaitSelector, Selector: req.Selector},
+			{Type: WaitTimeout, Timeout: time.Duration(req.WaitTime) * time.Second},
+		}
+	}
+	for _, cond := range waitConds {
+		action, err := cond.action()
+		if err != nil {
+			return nil, err
+		}
+		actions = append(actions, action)
+	}
+
+	var buf []byte
+	actions = append(actions, chromedp.ActionFunc(func(ctx context.Context) error {
+		shot, err := captureScreenshot(ctx, req)
+		if err != nil {
+			return err
+		}
+		buf = shot
+		return nil
+	}))
+
+	if err := chromedp.Run(ctx, actions...); err != nil {
+		return nil, err
+	}
+	return buf, nil
+}
+
+// captureScreenshot performs the actual page.CaptureScreenshot call,
+// handling full-page capture (forcing layout to the full content height and
+// capturing beyond the viewport) and an optional Clip rectangle.
+func captureScreenshot(ctx context.Context, req *Request) ([]byte, error) {
+	params := page.CaptureScreenshot().WithFormat(captureFormat(req.Format))
+	if req.Format == "jpeg" && req.Quality > 0 {
+		params = params.WithQuality(int64(req.Quality))
+	}
+
+	if req.FullPage {
+		var width, height float64
+		if err := chromedp.Evaluate(`document.documentElement.scrollWidth`, &width).Do(ctx); err != nil {
+			return nil, fmt.Errorf("failed to measure full page width: %w", err)
+		}
+		if err := chromedp.Evaluate(`document.documentElement.scrollHeight`, &height).Do(ctx); err != nil {
+			return nil, fmt.Errorf("failed to measure full page height: %w", err)
+		}
+		// Force the viewport to the full content size so
+		// CaptureBeyondViewport doesn't have to stitch anything together.
+		if err := emulation.SetDeviceMetricsOverride(int64(width), int64(height), 1, false).Do(ctx); err != nil {
+			return nil, fmt.Errorf("failed to expand viewport for full-page capture: %w", err)
+		}
+		params = params.WithClip(&page.Viewport{
+			X: 0, Y: 0,
+			Width: width, Height: height,
+			Scale: 1,
+		}).WithCaptureBeyondViewport(true)
+	} else if req.Clip != nil {
+		params = params.WithClip(&page.Viewport{
+			X: req.Clip.X, Y: req.Clip.Y,
+			Width: req.Clip.W, Height: req.Clip.H,
+			Scale: 1,
+		}).WithCaptureBeyondViewport(true)
+	}
+
+	return params.Do(ctx)
+}
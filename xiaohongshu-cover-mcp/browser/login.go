@@ -0,0 +1,171 @@
+package browser
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/chromedp"
+	"github.com/sirupsen/logrus"
+
+	"xiaohongshu-cover-mcp/cookies"
+)
+
+// creatorHomeURL is used purely as a login probe: an authenticated session
+// lands on the creator dashboard, an unauthenticated one gets redirected to
+// the login page.
+const creatorHomeURL = "https://creator.xiaohongshu.com/creator-micro/home"
+
+// loginPageURLFragment is matched against the tab's URL after navigating to
+// creatorHomeURL; its presence means the session's cookies didn't
+// authenticate and an interactive login is needed.
+const loginPageURLFragment = "login"
+
+// qrLoggedInSelector appears on creator.xiaohongshu.com once the QR code has
+// been scanned and accepted on the user's phone.
+const qrLoggedInSelector = ".creator-micro-home, .home-page"
+
+// sliderSelector matches Xiaohongshu's sliding-puzzle captcha widget, shown
+// occasionally during login.
+const sliderSelector = ".verify-slider, .slide-verify, #captcha-slider"
+
+// IsLoggedIn navigates the given tab to creator.xiaohongshu.com and reports
+// whether its current cookies are still authenticated, by checking whether
+// it was redirected to the login page.
+func IsLoggedIn(ctx context.Context, tabCtx context.Context) (bool, error) {
+	var currentURL string
+	if err := chromedp.Run(tabCtx,
+		chromedp.Navigate(creatorHomeURL),
+		chromedp.Sleep(2*time.Second),
+		chromedp.Location(&currentURL),
+	); err != nil {
+		return false, fmt.Errorf("failed to probe login status: %w", err)
+	}
+	return !containsLoginFragment(currentURL), nil
+}
+
+func containsLoginFragment(url string) bool {
+	for i := 0; i+len(loginPageURLFragment) <= len(url); i++ {
+		if url[i:i+len(loginPageURLFragment)] == loginPageURLFragment {
+			return true
+		}
+	}
+	return false
+}
+
+// EnsureLoggedIn checks whether the pool's active profile is still
+// authenticated and, if not, drives an interactive login in a dedicated
+// non-headless tab: it waits for the user to scan the QR code, handles a
+// sliding-puzzle captcha via solver (falling back to cookies.
+// ManualSliderSolver if solver is nil), then saves the resulting cookies
+// back to the profile and reloads every idle pooled tab with them.
+//
+// This borrows a pooled tab only to check IsLoggedIn; the actual
+// interactive login runs in its own throwaway allocator so a headless pool
+// doesn't have to be restarted non-headless just to let a human log in.
+func (p *Pool) EnsureLoggedIn(ctx context.Context, solver cookies.SliderSolver) error {
+	if p.profiles == nil {
+		return fmt.Errorf("no profile store configured")
+	}
+
+	page, err := p.Borrow(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to borrow a tab to check login status: %w", err)
+	}
+	loggedIn, err := IsLoggedIn(ctx, page.Ctx)
+	page.Release()
+	if err != nil {
+		return err
+	}
+	if loggedIn {
+		return nil
+	}
+
+	logrus.Infof("browser.Pool: profile %q is logged out, opening an interactive login window", p.profile)
+	newCookies, err := p.interactiveLogin(ctx, solver)
+	if err != nil {
+		return fmt.Errorf("interactive login failed: %w", err)
+	}
+
+	if err := p.profiles.Save(p.profile, newCookies); err != nil {
+		return fmt.Errorf("failed to save cookies after login: %w", err)
+	}
+	return p.reloadTabCookies(newCookies)
+}
+
+// interactiveLogin opens a throwaway non-headless browser, waits for the
+// user to scan the QR code (and solve a slider captcha, if one appears),
+// and returns the authenticated session's cookies.
+func (p *Pool) interactiveLogin(ctx context.Context, solver cookies.SliderSolver) ([]*network.CookieParam, error) {
+	allocOpts := append(chromedp.DefaultExecAllocatorOptions[:],
+		chromedp.Flag("headless", false),
+	)
+	allocCtx, allocCancel := chromedp.NewExecAllocator(ctx, allocOpts...)
+	defer allocCancel()
+
+	tabCtx, cancel := chromedp.NewContext(allocCtx, chromedp.WithLogf(logrus.Printf))
+	defer cancel()
+
+	if err := chromedp.Run(tabCtx, chromedp.Navigate(creatorHomeURL)); err != nil {
+		return nil, fmt.Errorf("failed to open login page: %w", err)
+	}
+
+	if solver == nil {
+		solver = cookies.ManualSliderSolver{Done: func(pageCtx context.Context) (bool, error) {
+			var present bool
+			if err := chromedp.Run(pageCtx, chromedp.Evaluate(
+				fmt.Sprintf(`document.querySelector(%q) === null`, sliderSelector), &present,
+			)); err != nil {
+				return false, err
+			}
+			return present, nil
+		}}
+	}
+
+	var hasSlider bool
+	if err := chromedp.Run(tabCtx, chromedp.Evaluate(
+		fmt.Sprintf(`document.querySelector(%q) !== null`, sliderSelector), &hasSlider,
+	)); err == nil && hasSlider {
+		logrus.Info("browser.Pool: slider captcha detected during login, handing off to SliderSolver")
+		if err := solver.Solve(ctx, tabCtx); err != nil {
+			return nil, fmt.Errorf("slider captcha not resolved: %w", err)
+		}
+	}
+
+	if err := chromedp.Run(tabCtx, chromedp.WaitVisible(qrLoggedInSelector, chromedp.ByQuery)); err != nil {
+		return nil, fmt.Errorf("timed out waiting for QR code login: %w", err)
+	}
+
+	var cookieList []*network.Cookie
+	if err := chromedp.Run(tabCtx, chromedp.ActionFunc(func(ctx context.Context) error {
+		var err error
+		cookieList, err = network.GetCookies().Do(ctx)
+		return err
+	})); err != nil {
+		return nil, fmt.Errorf("failed to read cookies after login: %w", err)
+	}
+	return toCookieParams(cookieList), nil
+}
+
+// reloadTabCookies pushes newCookies into every currently-idle pooled tab,
+// the same way SwitchProfile does, so the newly logged-in session takes
+// effect without a full pool restart.
+func (p *Pool) reloadTabCookies(newCookies []*network.CookieParam) error {
+	drained := make([]*tab, 0, p.size)
+	for i := 0; i < p.size; i++ {
+		select {
+		case t := <-p.tabs:
+			drained = append(drained, t)
+		default:
+		}
+	}
+	var firstErr error
+	for _, t := range drained {
+		if err := chromedp.Run(t.ctx, network.ClearCookies(), network.SetCookies(newCookies)); err != nil && firstErr == nil {
+			firstErr = fmt.Errorf("failed to apply refreshed cookies to a tab: %w", err)
+		}
+		p.tabs <- t
+	}
+	return firstErr
+}
@@ -0,0 +1,511 @@
+// Package browser provides a long-lived pool of chromedp tabs, so taking a
+// screenshot no longer pays the ~30s cost of spawning a fresh Chromium
+// process per call the way BrowserService.TakeScreenshot used to.
+package browser
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/chromedp"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/sirupsen/logrus"
+
+	"xiaohongshu-cover-mcp/cookies"
+	"xiaohongshu-cover-mcp/uploader"
+)
+
+var (
+	// queueDepth tracks how many callers are currently waiting for a free
+	// tab, so operators can tell the pool is undersized before requests
+	// start timing out.
+	queueDepth = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "xhs_cover_browser_pool_queue_depth",
+		Help: "Number of callers currently waiting for a free browser tab.",
+	})
+
+	// renderDuration tracks screenshot render time, with p50/p95 exposed
+	// directly via its quantile objectives.
+	renderDuration = promauto.NewSummary(prometheus.SummaryOpts{
+		Name:       "xhs_cover_browser_render_seconds",
+		Help:       "Screenshot render time in seconds, including navigation and retries.",
+		Objectives: map[float64]float64{0.5: 0.05, 0.95: 0.01},
+	})
+)
+
+// Request is a single screenshot job. Selector is matched with
+// chromedp.BySearch, so any CSS selector or XPath works, not just element
+// IDs. WaitTime is a legacy fallback used only when WaitFor is empty.
+type Request struct {
+	URL        string
+	Selector   string
+	OutputPath string
+	WaitTime   int
+
+	FullPage bool      // capture the full scrollable page, not just the viewport
+	Format   string    // "png" (default), "jpeg", or "webp"
+	Quality  int       // jpeg quality 0-100; ignored for png/webp
+	Viewport *Viewport // override the tab's viewport before capturing
+	Clip     *Clip     // capture only this sub-rectangle; ignored if FullPage
+	WaitFor  []WaitCond
+}
+
+// Result is the outcome of one screenshot job.
+type Result struct {
+	Success    bool
+	OutputPath string
+	Message    string
+	Error      string
+}
+
+// tab is one pooled chromedp tab plus the bookkeeping needed to recycle it
+// once it's been idle too long.
+type tab struct {
+	ctx      context.Context
+	cancel   context.CancelFunc
+	lastUsed time.Time
+}
+
+// PooledPage is a borrowed tab handed out by Pool.Borrow. Callers run
+// chromedp actions against Ctx and must call Release exactly once when
+// done, returning the tab to the pool instead of tearing it down.
+type PooledPage struct {
+	Ctx     context.Context
+	pool    *Pool
+	tab     *tab
+	release sync.Once
+}
+
+// Release returns the tab to the pool for reuse by the next Borrow/
+// TakeScreenshot call.
+func (p *PooledPage) Release() {
+	p.release.Do(func() {
+		p.tab.lastUsed = time.Now()
+		p.pool.release(p.tab)
+	})
+}
+
+// Pool maintains a fixed number of long-lived chromedp tabs, all derived
+// from a single shared browser allocator, so TakeScreenshot/
+// TakeScreenshotBatch/Borrow reuse a warm Chromium process instead of
+// launching one per call. Tabs idle longer than idleTTL are recycled (torn
+// down and respawned) on their next acquire. If a cookies.ProfileStore is
+// configured, the active profile's cookies are loaded into every warmed-up
+// tab and saved back on Close/SwitchProfile, so a restart - or a switch to
+// a different Xiaohongshu account - doesn't force a fresh login.
+type Pool struct {
+	allocCtx    context.Context
+	allocCancel context.CancelFunc
+	tabs        chan *tab
+
+	size           int
+	requestTimeout time.Duration
+	maxRetries     int
+	retryBackoff   time.Duration
+	idleTTL        time.Duration
+
+	profiles *cookies.ProfileStore
+	profile  string
+
+	uploader uploader.Uploader
+}
+
+// Option configures a Pool.
+type Option func(*Pool)
+
+// WithRequestTimeout overrides the per-request timeout (default 60s).
+func WithRequestTimeout(d time.Duration) Option {
+	return func(p *Pool) { p.requestTimeout = d }
+}
+
+// WithRetries overrides the retry count and initial backoff (default 2
+// retries, 2s initial backoff, doubling each attempt).
+func WithRetries(maxRetries int, initialBackoff time.Duration) Option {
+	return func(p *Pool) {
+		p.maxRetries = maxRetries
+		p.retryBackoff = initialBackoff
+	}
+}
+
+// WithIdleTTL overrides how long a tab may sit idle in the pool before
+// it's torn down and respawned on its next acquire (default 30m).
+func WithIdleTTL(d time.Duration) Option {
+	return func(p *Pool) { p.idleTTL = d }
+}
+
+// WithProfile persists this session's cookies under store, keyed by the
+// named profile, across restarts: they're loaded into every warmed-up tab
+// at NewPool time and saved back on Close/SwitchProfile, so a later
+// EnsureLoggedIn only has to open a login window once per account instead
+// of on every pool restart.
+func WithProfile(store *cookies.ProfileStore, profile string) Option {
+	return func(p *Pool) {
+		p.profiles = store
+		p.profile = profile
+	}
+}
+
+// WithUploader configures how Upload pushes a rendered image somewhere
+// fetchable: direct-to-Xiaohongshu via uploader.XHSUploader, a third-party
+// host via uploader.GenericUploader, or any other Uploader. Without this
+// option, Upload returns an error - callers fall back to publishing via the
+// page's own drag-and-drop uploader instead.
+func WithUploader(u uploader.Uploader) Option {
+	return func(p *Pool) { p.uploader = u }
+}
+
+// NewPool starts a single browser allocator and pre-warms size long-lived
+// tabs against it.
+func NewPool(headless bool, size int, opts ...Option) (*Pool, error) {
+	if size < 1 {
+		size = 1
+	}
+
+	allocOpts := append(chromedp.DefaultExecAllocatorOptions[:],
+		chromedp.Flag("headless", headless),
+		chromedp.Flag("disable-gpu", true),
+		chromedp.Flag("no-sandbox", true),
+		chromedp.Flag("disable-dev-shm-usage", true),
+		chromedp.Flag("disable-web-security", true),
+		chromedp.Flag("ignore-certificate-errors", true),
+		chromedp.Flag("ignore-ssl-errors", true),
+		chromedp.Flag("allow-running-insecure-content", true),
+	)
+
+	allocCtx, allocCancel := chromedp.NewExecAllocator(context.Background(), allocOpts...)
+
+	p := &Pool{
+		allocCtx:       allocCtx,
+		allocCancel:    allocCancel,
+		tabs:           make(chan *tab, size),
+		size:           size,
+		requestTimeout: 60 * time.Second,
+		maxRetries:     2,
+		retryBackoff:   2 * time.Second,
+		idleTTL:        30 * time.Minute,
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	var savedCookies []*network.CookieParam
+	if p.profiles != nil {
+		var err error
+		savedCookies, err = p.profiles.Load(p.profile)
+		if err != nil {
+			logrus.Warnf("browser.Pool: failed to load cookies for profile %q, starting logged out: %v", p.profile, err)
+		}
+	}
+
+	for i := 0; i < size; i++ {
+		t, err := p.spawnTab()
+		if err != nil {
+			p.allocCancel()
+			return nil, fmt.Errorf("failed to warm up browser tab %d/%d: %w", i+1, size, err)
+		}
+		if len(savedCookies) > 0 {
+			if err := chromedp.Run(t.ctx, network.SetCookies(savedCookies)); err != nil {
+				logrus.Warnf("browser.Pool: failed to apply cookies to tab %d/%d: %v", i+1, size, err)
+			}
+		}
+		p.tabs <- t
+	}
+
+	logrus.Infof("browser.Pool: warmed up %d tab(s)", size)
+	return p, nil
+}
+
+// spawnTab starts (and immediately runs, to pay Chromium's warm-up cost
+// here rather than on a caller's first request) one new tab.
+func (p *Pool) spawnTab() (*tab, error) {
+	ctx, cancel := chromedp.NewContext(p.allocCtx, chromedp.WithLogf(logrus.Printf))
+	if err := chromedp.Run(ctx); err != nil {
+		cancel()
+		return nil, err
+	}
+	return &tab{ctx: ctx, cancel: cancel, lastUsed: time.Now()}, nil
+}
+
+// Close saves the active profile's cookies (if configured) and tears down
+// every tab and the shared allocator.
+func (p *Pool) Close() {
+	if p.profiles != nil {
+		if err := p.saveCookies(p.profile); err != nil {
+			logrus.Warnf("browser.Pool: failed to save cookies for profile %q: %v", p.profile, err)
+		}
+	}
+	p.allocCancel()
+}
+
+// Profile returns the name of the currently active cookie profile ("" if
+// no ProfileStore is configured).
+func (p *Pool) Profile() string { return p.profile }
+
+// ListProfiles returns every profile with a saved cookie jar. It errors if
+// no ProfileStore is configured.
+func (p *Pool) ListProfiles() ([]string, error) {
+	if p.profiles == nil {
+		return nil, fmt.Errorf("no profile store configured")
+	}
+	return p.profiles.List()
+}
+
+// SwitchProfile saves the current profile's cookies, then loads profile's
+// saved cookies (if any) into every pooled tab and makes it the active
+// profile for subsequent Close/SwitchProfile calls. Tabs currently on loan
+// via Borrow/TakeScreenshot keep running under the old profile's cookies
+// until they're released and re-borrowed.
+func (p *Pool) SwitchProfile(profile string) error {
+	if p.profiles == nil {
+		return fmt.Errorf("no profile store configured")
+	}
+	if profile == p.profile {
+		return nil
+	}
+
+	if err := p.saveCookies(p.profile); err != nil {
+		logrus.Warnf("browser.Pool: failed to save cookies for profile %q before switching: %v", p.profile, err)
+	}
+
+	newCookies, err := p.profiles.Load(profile)
+	if err != nil {
+		return fmt.Errorf("failed to load profile %q: %w", profile, err)
+	}
+
+	drained := make([]*tab, 0, p.size)
+	for i := 0; i < p.size; i++ {
+		select {
+		case t := <-p.tabs:
+			drained = append(drained, t)
+		default:
+		}
+	}
+	for _, t := range drained {
+		if err := chromedp.Run(t.ctx, network.ClearCookies(), network.SetCookies(newCookies)); err != nil {
+			logrus.Warnf("browser.Pool: failed to apply profile %q cookies to a tab: %v", profile, err)
+		}
+		p.tabs <- t
+	}
+
+	p.profile = profile
+	return nil
+}
+
+// DeleteProfile removes profile's saved cookie jar. It refuses to delete
+// the currently active profile.
+func (p *Pool) DeleteProfile(profile string) error {
+	if p.profiles == nil {
+		return fmt.Errorf("no profile store configured")
+	}
+	if profile == p.profile {
+		return fmt.Errorf("cannot delete the active profile %q; switch profiles first", profile)
+	}
+	return p.profiles.Delete(profile)
+}
+
+// acquire blocks until a tab is available, recycling it first if it's been
+// idle longer than idleTTL, and tracking queue depth for callers that have
+// to wait.
+func (p *Pool) acquire(ctx context.Context) (*tab, error) {
+	select {
+	case t := <-p.tabs:
+		return p.recycleIfStale(t)
+	default:
+	}
+
+	queueDepth.Inc()
+	defer queueDepth.Dec()
+
+	select {
+	case t := <-p.tabs:
+		return p.recycleIfStale(t)
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// recycleIfStale tears down and respawns t if it's been sitting idle in
+// the pool longer than idleTTL.
+func (p *Pool) recycleIfStale(t *tab) (*tab, error) {
+	if time.Since(t.lastUsed) <= p.idleTTL {
+		return t, nil
+	}
+
+	logrus.Infof("browser.Pool: recycling tab idle for %s (ttl %s)", time.Since(t.lastUsed), p.idleTTL)
+	t.cancel()
+	fresh, err := p.spawnTab()
+	if err != nil {
+		return nil, fmt.Errorf("failed to recycle idle tab: %w", err)
+	}
+	return fresh, nil
+}
+
+func (p *Pool) release(t *tab) {
+	p.tabs <- t
+}
+
+// Borrow hands out a reusable tab for the caller to run arbitrary chromedp
+// actions against (e.g. a login flow). The caller must call
+// PooledPage.Release when done.
+func (p *Pool) Borrow(ctx context.Context) (*PooledPage, error) {
+	t, err := p.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &PooledPage{Ctx: t.ctx, pool: p, tab: t}, nil
+}
+
+// TakeScreenshot runs one screenshot job against a pooled tab, retrying
+// navigation failures with exponential backoff.
+func (p *Pool) TakeScreenshot(ctx context.Context, req *Request) (*Result, error) {
+	if req.Selector == "" {
+		req.Selector = "body"
+	}
+	if req.Format == "" {
+		req.Format = "png"
+	}
+	if req.OutputPath == "" {
+		req.OutputPath = fmt.Sprintf("screenshot_%d.%s", time.Now().Unix(), req.Format)
+	}
+	if req.WaitTime == 0 {
+		req.WaitTime = 3
+	}
+
+	page, err := p.Borrow(ctx)
+	if err != nil {
+		return &Result{Success: false, Error: err.Error()}, err
+	}
+	defer page.Release()
+
+	start := time.Now()
+	defer func() { renderDuration.Observe(time.Since(start).Seconds()) }()
+
+	var lastErr error
+	backoff := p.retryBackoff
+	for attempt := 0; attempt <= p.maxRetries; attempt++ {
+		if attempt > 0 {
+			logrus.Warnf("browser.Pool: retrying screenshot of %s (attempt %d/%d) after: %v", req.URL, attempt+1, p.maxRetries+1, lastErr)
+			time.Sleep(backoff)
+			backoff *= 2
+		}
+
+		runCtx, cancel := context.WithTimeout(page.Ctx, p.requestTimeout)
+		buf, err := capture(runCtx, req)
+		cancel()
+
+		if err == nil {
+			if dir := filepath.Dir(req.OutputPath); dir != "." {
+				if mkErr := ensureDir(dir); mkErr != nil {
+					return &Result{Success: false, Error: fmt.Sprintf("failed to create output directory: %v", mkErr)}, mkErr
+				}
+			}
+			if writeErr := ioutil.WriteFile(req.OutputPath, buf, 0644); writeErr != nil {
+				return &Result{Success: false, Error: fmt.Sprintf("failed to save screenshot: %v", writeErr)}, writeErr
+			}
+			return &Result{Success: true, OutputPath: req.OutputPath, Message: fmt.Sprintf("Screenshot saved successfully to %s", req.OutputPath)}, nil
+		}
+
+		lastErr = err
+	}
+
+	logrus.Errorf("browser.Pool: screenshot of %s failed after %d attempt(s): %v", req.URL, p.maxRetries+1, lastErr)
+	return &Result{Success: false, Error: lastErr.Error()}, lastErr
+}
+
+// TakeScreenshotBatch runs reqs concurrently, bounded by concurrency, and
+// returns results in the same order as reqs.
+func (p *Pool) TakeScreenshotBatch(ctx context.Context, reqs []*Request, concurrency int) []*Result {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	results := make([]*Result, len(reqs))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+
+	for i, req := range reqs {
+		i, req := i, req
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			result, err := p.TakeScreenshot(ctx, req)
+			if err != nil && result == nil {
+				result = &Result{Success: false, Error: err.Error()}
+			}
+			results[i] = result
+		}()
+	}
+
+	wg.Wait()
+	return results
+}
+
+// Upload pushes imgPath via the configured Uploader (see WithUploader) and
+// returns its hosted URL, bypassing the page's own drag-and-drop uploader
+// entirely - no tab is borrowed for this. It errors if no Uploader was
+// configured.
+func (p *Pool) Upload(ctx context.Context, imgPath string) (string, error) {
+	if p.uploader == nil {
+		return "", fmt.Errorf("no uploader configured")
+	}
+	return p.uploader.Upload(ctx, imgPath)
+}
+
+// saveCookies dumps the first pooled tab's cookie jar (via chromedp's
+// network.GetCookies) into p.profiles under profile, so a later NewPool/
+// SwitchProfile call picks up this session's login instead of starting
+// logged out.
+func (p *Pool) saveCookies(profile string) error {
+	var t *tab
+	select {
+	case t = <-p.tabs:
+		defer func() { p.tabs <- t }()
+	default:
+		return fmt.Errorf("no idle tab available to read cookies from")
+	}
+
+	var cookieList []*network.Cookie
+	if err := chromedp.Run(t.ctx, chromedp.ActionFunc(func(ctx context.Context) error {
+		var err error
+		cookieList, err = network.GetCookies().Do(ctx)
+		return err
+	})); err != nil {
+		return fmt.Errorf("failed to read cookies: %w", err)
+	}
+
+	return p.profiles.Save(profile, toCookieParams(cookieList))
+}
+
+// toCookieParams converts the Cookie values returned by network.GetCookies
+// into the CookieParam shape network.SetCookies expects, so a saved
+// session round-trips through NewPool's cookie load on the next start.
+func toCookieParams(cookieList []*network.Cookie) []*network.CookieParam {
+	params := make([]*network.CookieParam, len(cookieList))
+	for i, c := range cookieList {
+		params[i] = &network.CookieParam{
+			Name:     c.Name,
+			Value:    c.Value,
+			Domain:   c.Domain,
+			Path:     c.Path,
+			Secure:   c.Secure,
+			HTTPOnly: c.HTTPOnly,
+			SameSite: c.SameSite,
+			Expires:  c.Expires,
+		}
+	}
+	return params
+}
+
+// ensureDir creates dir (and any parents) if it doesn't already exist.
+func ensureDir(dir string) error {
+	return os.MkdirAll(dir, 0755)
+}
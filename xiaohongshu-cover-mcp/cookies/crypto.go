@@ -0,0 +1,137 @@
+package cookies
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+const (
+	encryptionKeySize = 32 // AES-256-GCM
+	hmacKeySize       = 64 // HMAC-SHA256
+)
+
+// encryptedMagic prefixes a sealed cookie file so Load can tell an
+// encrypted file from a legacy plaintext one by its header alone.
+var encryptedMagic = []byte("XHSENC1\n")
+
+// CookieKey holds the key material used to seal cookie files: a 32-byte
+// AES-256-GCM encryption key plus a 64-byte HMAC-SHA256 key, mirroring the
+// two-key convention gorilla/securecookie uses.
+type CookieKey struct {
+	Encryption []byte
+	HMAC       []byte
+}
+
+// GenerateCookieKey returns a fresh random key pair, hex-encoded, suitable
+// for XHS_COOKIE_KEY or a keyfile.
+func GenerateCookieKey() (string, error) {
+	raw := make([]byte, encryptionKeySize+hmacKeySize)
+	if _, err := io.ReadFull(rand.Reader, raw); err != nil {
+		return "", fmt.Errorf("failed to generate key material: %w", err)
+	}
+	return hex.EncodeToString(raw), nil
+}
+
+// LoadCookieKeyFromEnv hex-decodes the key from envVar (a 96-byte key: the
+// 32-byte encryption key followed by the 64-byte HMAC key).
+func LoadCookieKeyFromEnv(envVar string) (*CookieKey, error) {
+	encoded := os.Getenv(envVar)
+	if encoded == "" {
+		return nil, fmt.Errorf("env var %q is not set", envVar)
+	}
+	return parseCookieKey(encoded)
+}
+
+// LoadCookieKeyFromFile hex-decodes the key from a keyfile.
+func LoadCookieKeyFromFile(path string) (*CookieKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key file %q: %w", path, err)
+	}
+	return parseCookieKey(strings.TrimSpace(string(data)))
+}
+
+func parseCookieKey(encoded string) (*CookieKey, error) {
+	raw, err := hex.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("key is not valid hex: %w", err)
+	}
+	if len(raw) != encryptionKeySize+hmacKeySize {
+		return nil, fmt.Errorf("key must decode to %d bytes (got %d)", encryptionKeySize+hmacKeySize, len(raw))
+	}
+	return &CookieKey{Encryption: raw[:encryptionKeySize], HMAC: raw[encryptionKeySize:]}, nil
+}
+
+// seal encrypts plaintext with AES-256-GCM under key.Encryption, then
+// authenticates the (nonce || ciphertext) with HMAC-SHA256 under key.HMAC,
+// returning encryptedMagic || tag || nonce || ciphertext.
+func seal(key *CookieKey, plaintext []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key.Encryption)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	sealed := gcm.Seal(nonce, nonce, plaintext, nil)
+
+	mac := hmac.New(sha256.New, key.HMAC)
+	mac.Write(sealed)
+	tag := mac.Sum(nil)
+
+	out := make([]byte, 0, len(encryptedMagic)+len(tag)+len(sealed))
+	out = append(out, encryptedMagic...)
+	out = append(out, tag...)
+	out = append(out, sealed...)
+	return out, nil
+}
+
+// open reverses seal, verifying the HMAC tag before attempting decryption.
+func open(key *CookieKey, data []byte) ([]byte, error) {
+	data = data[len(encryptedMagic):]
+
+	if len(data) < sha256.Size {
+		return nil, fmt.Errorf("encrypted cookie file is truncated")
+	}
+	tag, sealed := data[:sha256.Size], data[sha256.Size:]
+
+	mac := hmac.New(sha256.New, key.HMAC)
+	mac.Write(sealed)
+	if !hmac.Equal(tag, mac.Sum(nil)) {
+		return nil, fmt.Errorf("cookie file failed HMAC verification (wrong key or tampered file)")
+	}
+
+	block, err := aes.NewCipher(key.Encryption)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < gcm.NonceSize() {
+		return nil, fmt.Errorf("encrypted cookie file is truncated")
+	}
+	nonce, ciphertext := sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():]
+	return gcm.Open(nil, nonce, ciphertext, nil)
+}
+
+// isEncrypted reports whether data starts with encryptedMagic.
+func isEncrypted(data []byte) bool {
+	return bytes.HasPrefix(data, encryptedMagic)
+}
@@ -0,0 +1,40 @@
+package cookies
+
+import (
+	"fmt"
+
+	"github.com/browserutils/kooky"
+	_ "github.com/browserutils/kooky/browser/all" // registers every supported Chrome/Edge/Firefox cookie store finder for the current OS
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/network"
+)
+
+// ImportFromLocalBrowser reads domain's cookies out of every Chrome/Edge/
+// Firefox profile installed on this machine - decrypting each browser's
+// OS-specific cookie store (DPAPI on Windows, Keychain on macOS, libsecret
+// or plaintext on Linux) via kooky - and saves the result under profile, so
+// a user can bootstrap a session without manually exporting cookies.
+func (s *ProfileStore) ImportFromLocalBrowser(profile, domain string) (int, error) {
+	found := kooky.ReadCookies(kooky.DomainContains(domain), kooky.Valid)
+	if len(found) == 0 {
+		return 0, fmt.Errorf("no cookies for domain %q found in any installed browser", domain)
+	}
+
+	params := make([]*network.CookieParam, 0, len(found))
+	for _, c := range found {
+		params = append(params, &network.CookieParam{
+			Name:     c.Name,
+			Value:    c.Value,
+			Domain:   c.Domain,
+			Path:     c.Path,
+			Secure:   c.Secure,
+			HTTPOnly: c.HttpOnly,
+			Expires:  cdp.TimeSinceEpoch(c.Expires),
+		})
+	}
+
+	if err := s.Save(profile, params); err != nil {
+		return 0, fmt.Errorf("failed to save imported cookies to profile %q: %w", profile, err)
+	}
+	return len(params), nil
+}
@@ -0,0 +1,55 @@
+package cookies
+
+import (
+	"context"
+	"time"
+)
+
+// SliderSolver solves the sliding-puzzle captcha Xiaohongshu sometimes shows
+// during interactive login. pageCtx is the chromedp context of the tab
+// showing the slider; implementations drag it (via mouse-move/mouse-release
+// actions, a third-party solving service, or similar) and return once the
+// captcha is cleared.
+type SliderSolver interface {
+	Solve(ctx context.Context, pageCtx context.Context) error
+}
+
+// ManualSliderSolver is the default SliderSolver: it just waits, giving a
+// human watching the non-headless window time to drag the slider
+// themselves, and polls done until it reports the captcha is gone.
+type ManualSliderSolver struct {
+	// Timeout bounds how long to wait for the human to finish (default 2m).
+	Timeout time.Duration
+	// Done is polled every 500ms; it should return true once the
+	// slider element is no longer present on the page.
+	Done func(pageCtx context.Context) (bool, error)
+}
+
+// Solve blocks until Done reports the slider is gone or Timeout elapses.
+func (m ManualSliderSolver) Solve(ctx context.Context, pageCtx context.Context) error {
+	timeout := m.Timeout
+	if timeout == 0 {
+		timeout = 2 * time.Minute
+	}
+	deadline := time.Now().Add(timeout)
+
+	ticker := time.NewTicker(500 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		if m.Done != nil {
+			if done, err := m.Done(pageCtx); err != nil {
+				return err
+			} else if done {
+				return nil
+			}
+		}
+		if time.Now().After(deadline) {
+			return context.DeadlineExceeded
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
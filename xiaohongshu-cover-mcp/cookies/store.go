@@ -0,0 +1,174 @@
+// Package cookies persists per-account cookie jars for the browser pool, so
+// a single running server can hold logged-in sessions for more than one
+// Xiaohongshu account at once instead of just one shared cookie file.
+package cookies
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"github.com/chromedp/cdproto/network"
+)
+
+// profileNamePattern restricts profile names to something safe to use as a
+// directory component, so a profile name can't escape baseDir.
+var profileNamePattern = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+
+// ProfileStore keeps each named profile's cookies under
+// baseDir/<profile>/cookies.json. If key is set, Save seals the file with
+// AES-256-GCM + an outer HMAC-SHA256 tag (see crypto.go); Load transparently
+// detects and decrypts a sealed file by its magic header, or falls back to
+// plain JSON for a legacy/unencrypted one.
+type ProfileStore struct {
+	baseDir string
+	key     *CookieKey
+}
+
+// NewProfileStore returns a ProfileStore rooted at baseDir (e.g.
+// "~/.xhs-mcp/profiles"), creating it if it doesn't exist. Cookie files are
+// stored as plain JSON; use NewEncryptedProfileStore to seal them.
+func NewProfileStore(baseDir string) (*ProfileStore, error) {
+	if err := os.MkdirAll(baseDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create profile store directory %q: %w", baseDir, err)
+	}
+	return &ProfileStore{baseDir: baseDir}, nil
+}
+
+// NewEncryptedProfileStore is NewProfileStore plus key: every profile saved
+// through the returned store is sealed with it, and any sealed file loaded
+// through it is decrypted with it. A profile file written before encryption
+// was enabled is still read fine (and gets sealed on its next Save).
+func NewEncryptedProfileStore(baseDir string, key *CookieKey) (*ProfileStore, error) {
+	s, err := NewProfileStore(baseDir)
+	if err != nil {
+		return nil, err
+	}
+	s.key = key
+	return s, nil
+}
+
+// Path returns the cookie file path for profile, without checking it exists.
+func (s *ProfileStore) Path(profile string) (string, error) {
+	if !profileNamePattern.MatchString(profile) {
+		return "", fmt.Errorf("invalid profile name %q: must match %s", profile, profileNamePattern.String())
+	}
+	return filepath.Join(s.baseDir, profile, "cookies.json"), nil
+}
+
+// Load reads profile's saved cookies. A profile with no saved cookies yet
+// just means a fresh, logged-out session.
+func (s *ProfileStore) Load(profile string) ([]*network.CookieParam, error) {
+	path, err := s.Path(profile)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if isEncrypted(data) {
+		if s.key == nil {
+			return nil, fmt.Errorf("cookie file %q is encrypted but no decryption key is configured", path)
+		}
+		if data, err = open(s.key, data); err != nil {
+			return nil, fmt.Errorf("failed to decrypt cookie file %q: %w", path, err)
+		}
+	}
+
+	var cookies []*network.CookieParam
+	if err := json.Unmarshal(data, &cookies); err != nil {
+		return nil, fmt.Errorf("failed to parse cookie file %q: %w", path, err)
+	}
+	return cookies, nil
+}
+
+// Save writes profile's cookies, creating the profile's directory if this
+// is its first save. The file is sealed with s.key if one is configured,
+// otherwise written as plain JSON.
+func (s *ProfileStore) Save(profile string, cookies []*network.CookieParam) error {
+	path, err := s.Path(profile)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create profile directory for %q: %w", profile, err)
+	}
+
+	data, err := json.Marshal(cookies)
+	if err != nil {
+		return fmt.Errorf("failed to encode cookies: %w", err)
+	}
+
+	if s.key != nil {
+		if data, err = seal(s.key, data); err != nil {
+			return fmt.Errorf("failed to encrypt cookies: %w", err)
+		}
+	}
+	return os.WriteFile(path, data, 0600)
+}
+
+// RotateKey re-encrypts every saved profile's cookie file under newKey. It
+// decrypts all of them with the store's current key first, so a wrong
+// current key fails before anything is overwritten; a failure partway
+// through re-saving can still leave some profiles under newKey and others
+// under the old key, which the caller should retry.
+func (s *ProfileStore) RotateKey(newKey *CookieKey) error {
+	profiles, err := s.List()
+	if err != nil {
+		return err
+	}
+
+	loaded := make(map[string][]*network.CookieParam, len(profiles))
+	for _, p := range profiles {
+		cookies, err := s.Load(p)
+		if err != nil {
+			return fmt.Errorf("failed to decrypt profile %q with the current key: %w", p, err)
+		}
+		loaded[p] = cookies
+	}
+
+	s.key = newKey
+	for p, cookies := range loaded {
+		if err := s.Save(p, cookies); err != nil {
+			return fmt.Errorf("failed to re-encrypt profile %q under the new key: %w", p, err)
+		}
+	}
+	return nil
+}
+
+// List returns every profile name with a saved cookie jar under baseDir.
+func (s *ProfileStore) List() ([]string, error) {
+	entries, err := os.ReadDir(s.baseDir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var profiles []string
+	for _, e := range entries {
+		if e.IsDir() {
+			profiles = append(profiles, e.Name())
+		}
+	}
+	return profiles, nil
+}
+
+// Delete removes profile's saved cookie jar (and its directory).
+func (s *ProfileStore) Delete(profile string) error {
+	path, err := s.Path(profile)
+	if err != nil {
+		return err
+	}
+	return os.RemoveAll(filepath.Dir(path))
+}
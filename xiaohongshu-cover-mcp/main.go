@@ -1,25 +1,125 @@
 package main
 
 import (
+	"context"
 	"flag"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
+	"github.com/chromedp/cdproto/network"
 	"github.com/sirupsen/logrus"
+
+	"xiaohongshu-cover-mcp/browser"
+	"xiaohongshu-cover-mcp/cookies"
+	"xiaohongshu-cover-mcp/uploader"
 )
 
 func main() {
+	// "xiaohongshu-cover-mcp cookies encrypt|decrypt|rotate-key" manages an
+	// encrypted profile store's key material without starting the server.
+	if len(os.Args) > 1 && os.Args[1] == "cookies" {
+		runCookiesCLI(os.Args[2:])
+		return
+	}
+
 	var (
-		headless bool
-		port     string
+		headless     bool
+		port         string
+		poolSize     int
+		stdio        bool
+		cookieDir    string
+		cookieKeyEnv string
+		profile      string
+		idleTTL      time.Duration
+		uploadMode   string
+		uploadURL    string
+		uploadField  string
+		uploadAuth   string
 	)
 	flag.BoolVar(&headless, "headless", true, "Run browser in headless mode")
 	flag.StringVar(&port, "port", ":18061", "Port to run MCP server on")
+	flag.IntVar(&poolSize, "pool-size", 4, "Number of long-lived browser tabs to keep warm")
+	flag.BoolVar(&stdio, "stdio", false, "Serve MCP over stdio instead of HTTP, for clients that launch this as a subprocess")
+	flag.StringVar(&cookieDir, "cookie-dir", "", "Directory to persist per-profile session cookies across restarts; empty disables persistence")
+	flag.StringVar(&cookieKeyEnv, "cookie-key-env", "XHS_COOKIE_KEY", "Env var holding the hex-encoded encryption+HMAC key to seal cookie files with; unset env var leaves them as plain JSON")
+	flag.StringVar(&profile, "profile", "default", "Name of the cookie profile (Xiaohongshu account) to start on")
+	flag.DurationVar(&idleTTL, "idle-ttl", 30*time.Minute, "How long a pooled tab may sit idle before it's recycled")
+	flag.StringVar(&uploadMode, "upload-mode", "", "Image upload backend to bypass drag-and-drop publishing: \"xhs\" or \"generic\"; empty disables it")
+	flag.StringVar(&uploadURL, "upload-url", "", "Upload endpoint URL (required for -upload-mode=generic)")
+	flag.StringVar(&uploadField, "upload-field", "file", "Multipart form field name the image is attached under (-upload-mode=generic)")
+	flag.StringVar(&uploadAuth, "upload-auth-header", "", "Raw \"Name: value\" auth header to send with each upload (-upload-mode=generic)")
 	flag.Parse()
 
-	// Initialize browser service
-	browserService := NewBrowserService(headless)
+	var profileStore *cookies.ProfileStore
+	poolOpts := []browser.Option{browser.WithIdleTTL(idleTTL)}
+	if cookieDir != "" {
+		var err error
+		if key := os.Getenv(cookieKeyEnv); key != "" {
+			cookieKey, keyErr := cookies.LoadCookieKeyFromEnv(cookieKeyEnv)
+			if keyErr != nil {
+				logrus.Fatalf("failed to load cookie encryption key from %s: %v", cookieKeyEnv, keyErr)
+			}
+			profileStore, err = cookies.NewEncryptedProfileStore(cookieDir, cookieKey)
+		} else {
+			profileStore, err = cookies.NewProfileStore(cookieDir)
+		}
+		if err != nil {
+			logrus.Fatalf("failed to open cookie profile store: %v", err)
+		}
+		poolOpts = append(poolOpts, browser.WithProfile(profileStore, profile))
+	}
+
+	switch uploadMode {
+	case "":
+		// Upload disabled; publishing falls back to drag-and-drop automation.
+	case "xhs":
+		var savedCookies []*network.CookieParam
+		if profileStore != nil {
+			savedCookies, _ = profileStore.Load(profile)
+		}
+		poolOpts = append(poolOpts, browser.WithUploader(uploader.NewXHSUploader(savedCookies)))
+	case "generic":
+		if uploadURL == "" {
+			logrus.Fatal("-upload-url is required when -upload-mode=generic")
+		}
+		poolOpts = append(poolOpts, browser.WithUploader(uploader.NewGenericUploader(uploadURL, uploadField, uploadAuth)))
+	default:
+		logrus.Fatalf("unknown -upload-mode %q (want \"xhs\" or \"generic\")", uploadMode)
+	}
+
+	// Initialize the browser pool; its tabs are warmed up here so the first
+	// screenshot doesn't pay Chromium's cold-start cost.
+	browserPool, err := browser.NewPool(headless, poolSize, poolOpts...)
+	if err != nil {
+		logrus.Fatalf("failed to start browser pool: %v", err)
+	}
+
+	// Close the pool (which also persists cookies, if configured) on a
+	// graceful shutdown, not just via the top-level defer, so a signal
+	// during a long-running stdio/HTTP server still saves the session.
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		<-sigChan
+		logrus.Info("Shutting down, saving browser session...")
+		browserPool.Close()
+		os.Exit(0)
+	}()
+	defer browserPool.Close()
 
 	// Create and start MCP server
-	mcpServer := NewMCPServer(browserService)
+	mcpServer := NewMCPServer(browserPool, profileStore)
+
+	if stdio {
+		logrus.Info("Serving MCP over stdio")
+		if err := mcpServer.rpc.ServeStdio(context.Background(), os.Stdin, os.Stdout); err != nil {
+			logrus.Fatalf("stdio MCP server failed: %v", err)
+		}
+		return
+	}
+
 	if err := mcpServer.Start(port); err != nil {
 		logrus.Fatalf("failed to run MCP server: %v", err)
 	}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"xiaohongshu-cover-mcp/cookies"
+)
+
+// runCookiesCLI implements "xiaohongshu-cover-mcp cookies <subcommand>",
+// for managing an encrypted profile store's key material independently of
+// running the MCP server.
+func runCookiesCLI(args []string) {
+	if len(args) == 0 {
+		fatalCookiesCLI("usage: xiaohongshu-cover-mcp cookies <encrypt|decrypt|rotate-key> [flags]")
+	}
+
+	switch args[0] {
+	case "encrypt":
+		fs := flag.NewFlagSet("cookies encrypt", flag.ExitOnError)
+		dir := fs.String("cookie-dir", "", "Cookie profile directory to encrypt in place")
+		keyEnv := fs.String("key-env", "XHS_COOKIE_KEY", "Env var holding the hex-encoded encryption+HMAC key")
+		fs.Parse(args[1:])
+		runCookiesEncrypt(*dir, *keyEnv)
+	case "decrypt":
+		fs := flag.NewFlagSet("cookies decrypt", flag.ExitOnError)
+		dir := fs.String("cookie-dir", "", "Cookie profile directory to decrypt in place")
+		keyEnv := fs.String("key-env", "XHS_COOKIE_KEY", "Env var holding the hex-encoded encryption+HMAC key")
+		fs.Parse(args[1:])
+		runCookiesDecrypt(*dir, *keyEnv)
+	case "rotate-key":
+		fs := flag.NewFlagSet("cookies rotate-key", flag.ExitOnError)
+		dir := fs.String("cookie-dir", "", "Cookie profile directory to re-key")
+		oldKeyEnv := fs.String("old-key-env", "XHS_COOKIE_KEY", "Env var holding the current hex-encoded key")
+		newKeyEnv := fs.String("new-key-env", "XHS_COOKIE_NEW_KEY", "Env var holding the new hex-encoded key")
+		generate := fs.Bool("generate", false, "Print a freshly generated hex-encoded key pair and exit, instead of rotating")
+		fs.Parse(args[1:])
+		runCookiesRotateKey(*dir, *oldKeyEnv, *newKeyEnv, *generate)
+	default:
+		fatalCookiesCLI("unknown cookies subcommand %q", args[0])
+	}
+}
+
+func runCookiesEncrypt(dir, keyEnv string) {
+	if dir == "" {
+		fatalCookiesCLI("cookies encrypt: -cookie-dir is required")
+	}
+	key, err := cookies.LoadCookieKeyFromEnv(keyEnv)
+	if err != nil {
+		fatalCookiesCLI("cookies encrypt: %v", err)
+	}
+
+	store, err := cookies.NewEncryptedProfileStore(dir, key)
+	if err != nil {
+		fatalCookiesCLI("cookies encrypt: %v", err)
+	}
+
+	profiles, err := store.List()
+	if err != nil {
+		fatalCookiesCLI("cookies encrypt: %v", err)
+	}
+	for _, p := range profiles {
+		cookieList, err := store.Load(p)
+		if err != nil {
+			fatalCookiesCLI("cookies encrypt: failed to load profile %q: %v", p, err)
+		}
+		if err := store.Save(p, cookieList); err != nil {
+			fatalCookiesCLI("cookies encrypt: failed to save profile %q: %v", p, err)
+		}
+	}
+	fmt.Printf("Encrypted %d profile(s) under %s\n", len(profiles), dir)
+}
+
+func runCookiesDecrypt(dir, keyEnv string) {
+	if dir == "" {
+		fatalCookiesCLI("cookies decrypt: -cookie-dir is required")
+	}
+	key, err := cookies.LoadCookieKeyFromEnv(keyEnv)
+	if err != nil {
+		fatalCookiesCLI("cookies decrypt: %v", err)
+	}
+
+	encStore, err := cookies.NewEncryptedProfileStore(dir, key)
+	if err != nil {
+		fatalCookiesCLI("cookies decrypt: %v", err)
+	}
+	plainStore, err := cookies.NewProfileStore(dir)
+	if err != nil {
+		fatalCookiesCLI("cookies decrypt: %v", err)
+	}
+
+	profiles, err := encStore.List()
+	if err != nil {
+		fatalCookiesCLI("cookies decrypt: %v", err)
+	}
+	for _, p := range profiles {
+		cookieList, err := encStore.Load(p)
+		if err != nil {
+			fatalCookiesCLI("cookies decrypt: failed to load profile %q: %v", p, err)
+		}
+		if err := plainStore.Save(p, cookieList); err != nil {
+			fatalCookiesCLI("cookies decrypt: failed to save profile %q: %v", p, err)
+		}
+	}
+	fmt.Printf("Decrypted %d profile(s) under %s\n", len(profiles), dir)
+}
+
+func runCookiesRotateKey(dir, oldKeyEnv, newKeyEnv string, generate bool) {
+	if generate {
+		key, err := cookies.GenerateCookieKey()
+		if err != nil {
+			fatalCookiesCLI("cookies rotate-key: %v", err)
+		}
+		fmt.Println(key)
+		return
+	}
+
+	if dir == "" {
+		fatalCookiesCLI("cookies rotate-key: -cookie-dir is required (or pass -generate to just print a new key)")
+	}
+	oldKey, err := cookies.LoadCookieKeyFromEnv(oldKeyEnv)
+	if err != nil {
+		fatalCookiesCLI("cookies rotate-key: failed to load current key from %s: %v", oldKeyEnv, err)
+	}
+	newKey, err := cookies.LoadCookieKeyFromEnv(newKeyEnv)
+	if err != nil {
+		fatalCookiesCLI("cookies rotate-key: failed to load new key from %s: %v", newKeyEnv, err)
+	}
+
+	store, err := cookies.NewEncryptedProfileStore(dir, oldKey)
+	if err != nil {
+		fatalCookiesCLI("cookies rotate-key: %v", err)
+	}
+	if err := store.RotateKey(newKey); err != nil {
+		fatalCookiesCLI("cookies rotate-key: %v", err)
+	}
+	fmt.Println("Rotated cookie encryption key")
+}
+
+func fatalCookiesCLI(format string, args ...interface{}) {
+	fmt.Fprintf(os.Stderr, format+"\n", args...)
+	os.Exit(1)
+}
@@ -0,0 +1,51 @@
+package mcp
+
+import "context"
+
+// Notification is a one-way JSON-RPC 2.0 message: a server-to-client push
+// with no id, so it never gets a Response. "notifications/progress" and
+// "notifications/cancelled" acks both take this shape.
+type Notification struct {
+	JSONRPC string      `json:"jsonrpc"`
+	Method  string      `json:"method"`
+	Params  interface{} `json:"params,omitempty"`
+}
+
+// Notifier lets a Handler push a Notification to the client that made the
+// in-flight request, before returning its final result - used for
+// long-running tools/call invocations to report progress. Transports that
+// can't stream (a plain POST with no SSE upgrade) attach a no-op Notifier.
+type Notifier func(method string, params interface{})
+
+type notifierCtxKey struct{}
+type progressTokenCtxKey struct{}
+
+// WithNotifier attaches a Notifier to ctx for a Handler to retrieve via
+// NotifierFromContext.
+func WithNotifier(ctx context.Context, n Notifier) context.Context {
+	return context.WithValue(ctx, notifierCtxKey{}, n)
+}
+
+// NotifierFromContext returns the Notifier attached to ctx, or a no-op if
+// the request arrived over a transport that can't stream notifications
+// back.
+func NotifierFromContext(ctx context.Context) Notifier {
+	if n, ok := ctx.Value(notifierCtxKey{}).(Notifier); ok && n != nil {
+		return n
+	}
+	return func(string, interface{}) {}
+}
+
+// WithProgressToken attaches a tools/call request's params._meta.progressToken
+// to ctx, so a Handler knows the client asked to be kept updated and under
+// which token to report it.
+func WithProgressToken(ctx context.Context, token interface{}) context.Context {
+	return context.WithValue(ctx, progressTokenCtxKey{}, token)
+}
+
+// ProgressTokenFromContext returns the progressToken attached to ctx, if
+// the in-flight request's params carried one.
+func ProgressTokenFromContext(ctx context.Context) (interface{}, bool) {
+	token := ctx.Value(progressTokenCtxKey{})
+	return token, token != nil
+}
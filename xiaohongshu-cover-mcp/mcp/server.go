@@ -0,0 +1,485 @@
+// Package mcp implements a transport-agnostic JSON-RPC 2.0 dispatcher for
+// MCP-style method handlers: batch requests, notifications (no response for
+// requests with no "id"), standard error codes, a method registry with
+// per-method param schemas, "$/cancelRequest"/"notifications/cancelled"
+// propagation into the handler's context.Context, and (over HTTP) the
+// streamable-HTTP transport: a client that sends Accept: text/event-stream
+// gets its responses and any interim Notifier pushes back as SSE events
+// instead of one JSON body, and GET opens a stream for server-initiated
+// Broadcast notifications.
+package mcp
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// Standard JSON-RPC 2.0 error codes (https://www.jsonrpc.org/specification#error_object).
+const (
+	ParseError     = -32700
+	InvalidRequest = -32600
+	MethodNotFound = -32601
+	InvalidParams  = -32602
+	InternalError  = -32603
+)
+
+// Reserved range for MCP/application-defined errors (-32000 to -32099 per
+// the JSON-RPC spec's "Server error" reservation).
+const (
+	ErrToolExecution = -32000
+	ErrRateLimited   = -32001
+	ErrCancelled     = -32002
+)
+
+// Request is a single JSON-RPC 2.0 request or notification. A nil ID marks
+// a notification: dispatch still runs the handler but HandleMessage never
+// produces a response for it.
+type Request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	ID      interface{}     `json:"id,omitempty"`
+}
+
+// Response is a single JSON-RPC 2.0 response.
+type Response struct {
+	JSONRPC string      `json:"jsonrpc"`
+	Result  interface{} `json:"result,omitempty"`
+	Error   *Error      `json:"error,omitempty"`
+	ID      interface{} `json:"id"`
+}
+
+// Error is a JSON-RPC 2.0 error object.
+type Error struct {
+	Code    int         `json:"code"`
+	Message string      `json:"message"`
+	Data    interface{} `json:"data,omitempty"`
+}
+
+// Handler processes one method call's params and returns either a result or
+// an *Error. ctx is cancelled if a matching "$/cancelRequest" notification
+// arrives while the handler is running.
+type Handler func(ctx context.Context, params json.RawMessage) (interface{}, *Error)
+
+type methodEntry struct {
+	schema  map[string]interface{}
+	handler Handler
+}
+
+// Server is a JSON-RPC 2.0 dispatcher: register methods once, then feed it
+// raw messages from HTTP, stdio, or any other transport via HandleMessage.
+type Server struct {
+	mu      sync.RWMutex
+	methods map[string]*methodEntry
+
+	cancelMu  sync.Mutex
+	cancelFns map[interface{}]context.CancelFunc
+
+	subsMu sync.Mutex
+	subs   map[chan Notification]struct{}
+}
+
+// NewServer creates an empty Server; call Register before serving traffic.
+func NewServer() *Server {
+	return &Server{
+		methods:   make(map[string]*methodEntry),
+		cancelFns: make(map[interface{}]context.CancelFunc),
+		subs:      make(map[chan Notification]struct{}),
+	}
+}
+
+// Broadcast pushes a server-initiated Notification to every client
+// currently connected over GET /mcp's SSE stream. Unlike a Notifier
+// returned from a request's context, this isn't tied to any particular
+// in-flight call.
+func (s *Server) Broadcast(method string, params interface{}) {
+	n := Notification{JSONRPC: "2.0", Method: method, Params: params}
+
+	s.subsMu.Lock()
+	defer s.subsMu.Unlock()
+	for ch := range s.subs {
+		select {
+		case ch <- n:
+		default: // slow subscriber; drop rather than block the broadcaster
+		}
+	}
+}
+
+func (s *Server) subscribe() chan Notification {
+	ch := make(chan Notification, 16)
+	s.subsMu.Lock()
+	s.subs[ch] = struct{}{}
+	s.subsMu.Unlock()
+	return ch
+}
+
+func (s *Server) unsubscribe(ch chan Notification) {
+	s.subsMu.Lock()
+	delete(s.subs, ch)
+	s.subsMu.Unlock()
+}
+
+// Register adds a method to the registry. schema is an optional JSON Schema
+// object used to validate params' required fields before handler runs; pass
+// nil to skip validation.
+func (s *Server) Register(name string, schema map[string]interface{}, handler Handler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.methods[name] = &methodEntry{schema: schema, handler: handler}
+}
+
+// Schemas returns every registered method's name and param schema, for
+// building a tools/list-style introspection response.
+func (s *Server) Schemas() map[string]map[string]interface{} {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	out := make(map[string]map[string]interface{}, len(s.methods))
+	for name, m := range s.methods {
+		out[name] = m.schema
+	}
+	return out
+}
+
+// HandleMessage dispatches a single JSON-RPC request or a batch array and
+// returns the framed response bytes to write back, or nil if nothing needs
+// a reply (a notification, or a batch made up entirely of notifications).
+func (s *Server) HandleMessage(ctx context.Context, raw []byte) []byte {
+	trimmed := bytes.TrimSpace(raw)
+	if len(trimmed) == 0 {
+		return nil
+	}
+
+	if trimmed[0] == '[' {
+		var reqs []Request
+		if err := json.Unmarshal(trimmed, &reqs); err != nil {
+			return encode(errorResponse(nil, ParseError, "Parse error", err.Error()))
+		}
+
+		responses := make([]*Response, 0, len(reqs))
+		for _, req := range reqs {
+			if resp := s.dispatch(ctx, req); resp != nil {
+				responses = append(responses, resp)
+			}
+		}
+		if len(responses) == 0 {
+			return nil
+		}
+		return encode(responses)
+	}
+
+	var req Request
+	if err := json.Unmarshal(trimmed, &req); err != nil {
+		return encode(errorResponse(nil, ParseError, "Parse error", err.Error()))
+	}
+
+	resp := s.dispatch(ctx, req)
+	if resp == nil {
+		return nil
+	}
+	return encode(resp)
+}
+
+// ServeHTTP implements http.Handler and speaks MCP's streamable HTTP
+// transport: a GET opens an SSE stream for server-initiated notifications
+// (see Broadcast); a POST dispatches one request or batch, replying either
+// as a single JSON body or, when the client's Accept header includes
+// "text/event-stream", as a stream of SSE "data:" events - one per interim
+// notification (e.g. tools/call progress) plus a final one per response.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		s.serveNotificationStream(w, r)
+		return
+	case http.MethodPost:
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(encode(errorResponse(nil, ParseError, "Parse error", err.Error())))
+		return
+	}
+
+	if acceptsEventStream(r) {
+		s.handleMessageSSE(r.Context(), body, w)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if resp := s.HandleMessage(r.Context(), body); resp != nil {
+		w.Write(resp)
+	} else {
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func acceptsEventStream(r *http.Request) bool {
+	return strings.Contains(r.Header.Get("Accept"), "text/event-stream")
+}
+
+// handleMessageSSE is ServeHTTP's streaming path: it parses raw the same
+// way HandleMessage does, but gives every dispatched request a Notifier
+// that writes straight to the SSE stream, then writes each response as its
+// own "data:" event instead of collecting them into one JSON body.
+func (s *Server) handleMessageSSE(ctx context.Context, raw []byte, w http.ResponseWriter) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		w.Header().Set("Content-Type", "application/json")
+		if resp := s.HandleMessage(ctx, raw); resp != nil {
+			w.Write(resp)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+
+	writeEvent := func(v interface{}) {
+		fmt.Fprintf(w, "data: %s\n\n", encode(v))
+		flusher.Flush()
+	}
+	notify := func(method string, params interface{}) {
+		writeEvent(Notification{JSONRPC: "2.0", Method: method, Params: params})
+	}
+
+	trimmed := bytes.TrimSpace(raw)
+	if len(trimmed) == 0 {
+		return
+	}
+
+	if trimmed[0] == '[' {
+		var reqs []Request
+		if err := json.Unmarshal(trimmed, &reqs); err != nil {
+			writeEvent(errorResponse(nil, ParseError, "Parse error", err.Error()))
+			return
+		}
+		for _, req := range reqs {
+			if resp := s.dispatch(WithNotifier(ctx, notify), req); resp != nil {
+				writeEvent(resp)
+			}
+		}
+		return
+	}
+
+	var req Request
+	if err := json.Unmarshal(trimmed, &req); err != nil {
+		writeEvent(errorResponse(nil, ParseError, "Parse error", err.Error()))
+		return
+	}
+	if resp := s.dispatch(WithNotifier(ctx, notify), req); resp != nil {
+		writeEvent(resp)
+	}
+}
+
+// serveNotificationStream backs GET /mcp: it subscribes the connection to
+// every future Broadcast call and streams them as SSE events until the
+// client disconnects.
+func (s *Server) serveNotificationStream(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusNotImplemented)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	ch := s.subscribe()
+	defer s.unsubscribe(ch)
+
+	for {
+		select {
+		case n := <-ch:
+			fmt.Fprintf(w, "data: %s\n\n", encode(n))
+			flusher.Flush()
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+// ServeStdio reads newline-delimited JSON-RPC messages (single objects or
+// batch arrays) from r - the transport an MCP client speaks when it
+// launches the server as a subprocess instead of talking HTTP - and writes
+// each non-empty response back to w on its own line until r is exhausted or
+// ctx is cancelled. Messages are handled one at a time, so a
+// "$/cancelRequest" read from r cannot abort a call that is already running.
+func (s *Server) ServeStdio(ctx context.Context, r io.Reader, w io.Writer) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	for scanner.Scan() {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		line := scanner.Bytes()
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+
+		resp := s.HandleMessage(ctx, line)
+		if resp == nil {
+			continue
+		}
+		if _, err := w.Write(append(resp, '\n')); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+// dispatch routes a single request to its handler. Returns nil for
+// notifications (req.ID == nil), since the JSON-RPC spec forbids a
+// response to those.
+func (s *Server) dispatch(ctx context.Context, req Request) *Response {
+	isNotification := req.ID == nil
+
+	if req.JSONRPC != "2.0" || req.Method == "" {
+		if isNotification {
+			return nil
+		}
+		return errorResponse(req.ID, InvalidRequest, "Invalid Request", `"jsonrpc" must be "2.0" and "method" must be set`)
+	}
+
+	// $/cancelRequest (LSP-style) and notifications/cancelled (MCP spec)
+	// are both always notifications: neither gets a response, even if a
+	// client mistakenly sends one with an id.
+	if req.Method == "$/cancelRequest" || req.Method == "notifications/cancelled" {
+		s.handleCancelRequest(req.Params)
+		return nil
+	}
+
+	s.mu.RLock()
+	m, ok := s.methods[req.Method]
+	s.mu.RUnlock()
+	if !ok {
+		if isNotification {
+			return nil
+		}
+		return errorResponse(req.ID, MethodNotFound, "Method not found", fmt.Sprintf("unknown method: %s", req.Method))
+	}
+
+	if m.schema != nil {
+		if err := validateRequired(req.Params, m.schema); err != nil {
+			if isNotification {
+				return nil
+			}
+			return errorResponse(req.ID, InvalidParams, "Invalid params", err.Error())
+		}
+	}
+
+	callCtx := ctx
+	if !isNotification {
+		var cancel context.CancelFunc
+		callCtx, cancel = context.WithCancel(ctx)
+		s.registerCancel(req.ID, cancel)
+		defer s.unregisterCancel(req.ID)
+	}
+
+	result, rpcErr := m.handler(callCtx, req.Params)
+	if isNotification {
+		return nil
+	}
+	if rpcErr != nil {
+		return &Response{JSONRPC: "2.0", Error: rpcErr, ID: req.ID}
+	}
+	return &Response{JSONRPC: "2.0", Result: result, ID: req.ID}
+}
+
+// handleCancelRequest looks up the cancel func for the request ID named in
+// a "$/cancelRequest" (params.id) or "notifications/cancelled"
+// (params.requestId) notification and invokes it, aborting the
+// corresponding in-flight call's context.Context.
+func (s *Server) handleCancelRequest(params json.RawMessage) {
+	var p struct {
+		ID        interface{} `json:"id"`
+		RequestID interface{} `json:"requestId"`
+	}
+	if err := json.Unmarshal(params, &p); err != nil {
+		return
+	}
+
+	id := p.ID
+	if id == nil {
+		id = p.RequestID
+	}
+	if id == nil {
+		return
+	}
+
+	s.cancelMu.Lock()
+	cancel, ok := s.cancelFns[id]
+	s.cancelMu.Unlock()
+	if ok {
+		cancel()
+	}
+}
+
+func (s *Server) registerCancel(id interface{}, cancel context.CancelFunc) {
+	s.cancelMu.Lock()
+	defer s.cancelMu.Unlock()
+	s.cancelFns[id] = cancel
+}
+
+func (s *Server) unregisterCancel(id interface{}) {
+	s.cancelMu.Lock()
+	defer s.cancelMu.Unlock()
+	delete(s.cancelFns, id)
+}
+
+// validateRequired checks that every field named in schema["required"] is
+// present in params. It's intentionally shallow - just enough to catch a
+// missing field before the handler runs, not a full JSON Schema validator.
+func validateRequired(params json.RawMessage, schema map[string]interface{}) error {
+	required, _ := schema["required"].([]interface{})
+	if len(required) == 0 {
+		return nil
+	}
+
+	args := map[string]interface{}{}
+	if len(params) > 0 {
+		if err := json.Unmarshal(params, &args); err != nil {
+			return fmt.Errorf("params must be an object: %w", err)
+		}
+	}
+
+	for _, field := range required {
+		name, _ := field.(string)
+		if _, ok := args[name]; !ok {
+			return fmt.Errorf("missing required field %q", name)
+		}
+	}
+	return nil
+}
+
+func errorResponse(id interface{}, code int, message, data string) *Response {
+	return &Response{
+		JSONRPC: "2.0",
+		Error:   &Error{Code: code, Message: message, Data: data},
+		ID:      id,
+	}
+}
+
+func encode(v interface{}) []byte {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return []byte(`{"jsonrpc":"2.0","error":{"code":-32603,"message":"Internal error"},"id":null}`)
+	}
+	return data
+}
@@ -6,18 +6,22 @@ import (
 	"fmt"
 	"net/url"
 	"strconv"
+	"time"
 
 	"github.com/sirupsen/logrus"
+
+	"xiaohongshu-cover-mcp/browser"
+	"xiaohongshu-cover-mcp/mcp"
 )
 
 const defaultText = `
 8 月 3 日入园人数: <span style="color: #ff0000; font-weight: bold;">19999</span><br/>天气晴朗适合游玩
 `
 
-// handleGenerateXiaohongshuCover handles the generate_xiaohongshu_cover tool call
-func (s *MCPServer) handleGenerateXiaohongshuCover(ctx context.Context, args map[string]interface{}) *MCPToolResult {
-	logrus.Info("MCP: Generating Xiaohongshu cover")
-
+// buildScreenshotRequest applies the ad-hoc defaults map and builds the
+// cover page URL + browser.Request for a single set of args. It's shared by
+// the single-cover and batch handlers so they stay consistent.
+func buildScreenshotRequest(args map[string]interface{}) *browser.Request {
 	// Set default values
 	defaults := map[string]interface{}{
 		"baseUrl":         "http://localhost:3000",
@@ -81,20 +85,72 @@ func (s *MCPServer) handleGenerateXiaohongshuCover(ctx context.Context, args map
 	// Construct full URL
 	fullURL := baseURL + "?" + urlParams.Encode()
 
-	logrus.Infof("MCP: Generated URL: %s", fullURL)
-
-	// Build screenshot request
-	req := &ScreenshotRequest{
+	return &browser.Request{
 		URL:        fullURL,
 		Selector:   args["selector"].(string),
 		OutputPath: outputPath,
 		WaitTime:   5, // Wait longer for the page to render
 	}
+}
+
+// reportProgress sends interim "notifications/progress" events for a
+// long-running tool call, ticking until the returned stop func is called.
+// It's a no-op unless the request carried a progressToken - i.e. the
+// client asked to be kept updated and the transport can stream
+// notifications back (see mcp.Server's SSE path).
+func (s *MCPServer) reportProgress(ctx context.Context) func() {
+	token, ok := mcp.ProgressTokenFromContext(ctx)
+	if !ok {
+		return func() {}
+	}
+	notify := mcp.NotifierFromContext(ctx)
 
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(2 * time.Second)
+		defer ticker.Stop()
+		progress := 0
+		for {
+			select {
+			case <-ticker.C:
+				if progress < 90 {
+					progress += 15
+				}
+				notify("notifications/progress", map[string]interface{}{
+					"progressToken": token,
+					"progress":      progress,
+					"total":         100,
+				})
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return func() {
+		close(done)
+		notify("notifications/progress", map[string]interface{}{
+			"progressToken": token,
+			"progress":      100,
+			"total":         100,
+		})
+	}
+}
+
+// handleGenerateXiaohongshuCover handles the generate_xiaohongshu_cover tool call
+func (s *MCPServer) handleGenerateXiaohongshuCover(ctx context.Context, args map[string]interface{}) *MCPToolResult {
+	logrus.Info("MCP: Generating Xiaohongshu cover")
+
+	req := buildScreenshotRequest(args)
+
+	logrus.Infof("MCP: Generated URL: %s", req.URL)
 	logrus.Infof("MCP: Taking screenshot for Xiaohongshu cover generation")
 
+	stopProgress := s.reportProgress(ctx)
+	defer stopProgress()
+
 	// Execute screenshot
-	result, err := s.browserService.TakeScreenshot(ctx, req)
+	result, err := s.browserPool.TakeScreenshot(ctx, req)
 	if err != nil {
 		return &MCPToolResult{
 			Content: []MCPContent{{
@@ -120,8 +176,170 @@ func (s *MCPServer) handleGenerateXiaohongshuCover(ctx context.Context, args map
 	return &MCPToolResult{
 		Content: []MCPContent{{
 			Type: "text",
-			Text: fmt.Sprintf("Xiaohongshu cover generated successfully:\n%s\n\nGenerated URL: %s", string(jsonData), fullURL),
+			Text: fmt.Sprintf("Xiaohongshu cover generated successfully:\n%s\n\nGenerated URL: %s", string(jsonData), req.URL),
+		}},
+	}
+}
+
+// handleGenerateXiaohongshuCoversBatch handles the
+// generate_xiaohongshu_covers_batch tool call: it builds one browser.Request
+// per entry in the "covers" array and renders them concurrently against the
+// warm browser pool, bounded by the optional "concurrency" argument.
+func (s *MCPServer) handleGenerateXiaohongshuCoversBatch(ctx context.Context, args map[string]interface{}) *MCPToolResult {
+	covers, ok := args["covers"].([]interface{})
+	if !ok || len(covers) == 0 {
+		return &MCPToolResult{
+			Content: []MCPContent{{
+				Type: "text",
+				Text: "Missing or empty \"covers\" array",
+			}},
+			IsError: true,
+		}
+	}
+
+	logrus.Infof("MCP: Generating %d Xiaohongshu covers in batch", len(covers))
+
+	reqs := make([]*browser.Request, 0, len(covers))
+	for _, cover := range covers {
+		coverArgs, ok := cover.(map[string]interface{})
+		if !ok {
+			coverArgs = map[string]interface{}{}
+		}
+		reqs = append(reqs, buildScreenshotRequest(coverArgs))
+	}
+
+	concurrency, _ := args["concurrency"].(float64)
+	if concurrency <= 0 {
+		concurrency = float64(len(reqs))
+	}
+
+	results := s.browserPool.TakeScreenshotBatch(ctx, reqs, int(concurrency))
+
+	jsonData, err := json.MarshalIndent(results, "", "  ")
+	if err != nil {
+		return &MCPToolResult{
+			Content: []MCPContent{{
+				Type: "text",
+				Text: fmt.Sprintf("Failed to format batch result: %s", err.Error()),
+			}},
+			IsError: true,
+		}
+	}
+
+	return &MCPToolResult{
+		Content: []MCPContent{{
+			Type: "text",
+			Text: fmt.Sprintf("Generated %d/%d Xiaohongshu covers:\n%s", countSuccesses(results), len(results), string(jsonData)),
 		}},
 	}
 }
 
+// countSuccesses counts how many batch results succeeded, for the summary
+// line in handleGenerateXiaohongshuCoversBatch's response.
+func countSuccesses(results []*browser.Result) int {
+	count := 0
+	for _, r := range results {
+		if r != nil && r.Success {
+			count++
+		}
+	}
+	return count
+}
+
+// errorResult builds a single-line MCPToolResult reporting a tool failure.
+func errorResult(format string, args ...interface{}) *MCPToolResult {
+	return &MCPToolResult{
+		Content: []MCPContent{{Type: "text", Text: fmt.Sprintf(format, args...)}},
+		IsError: true,
+	}
+}
+
+// textResult builds a single-line successful MCPToolResult.
+func textResult(format string, args ...interface{}) *MCPToolResult {
+	return &MCPToolResult{Content: []MCPContent{{Type: "text", Text: fmt.Sprintf(format, args...)}}}
+}
+
+// handleListProfiles handles the list_cookie_profiles tool call.
+func (s *MCPServer) handleListProfiles(ctx context.Context, args map[string]interface{}) *MCPToolResult {
+	profiles, err := s.browserPool.ListProfiles()
+	if err != nil {
+		return errorResult("Failed to list cookie profiles: %s", err.Error())
+	}
+	return textResult("Active profile: %s\nSaved profiles: %v", s.browserPool.Profile(), profiles)
+}
+
+// handleSwitchProfile handles the switch_cookie_profile tool call.
+func (s *MCPServer) handleSwitchProfile(ctx context.Context, args map[string]interface{}) *MCPToolResult {
+	name, _ := args["profile"].(string)
+	if name == "" {
+		return errorResult("Missing required argument \"profile\"")
+	}
+	if err := s.browserPool.SwitchProfile(name); err != nil {
+		return errorResult("Failed to switch to profile %q: %s", name, err.Error())
+	}
+	return textResult("Switched active cookie profile to %q", name)
+}
+
+// handleDeleteProfile handles the delete_cookie_profile tool call.
+func (s *MCPServer) handleDeleteProfile(ctx context.Context, args map[string]interface{}) *MCPToolResult {
+	name, _ := args["profile"].(string)
+	if name == "" {
+		return errorResult("Missing required argument \"profile\"")
+	}
+	if err := s.browserPool.DeleteProfile(name); err != nil {
+		return errorResult("Failed to delete profile %q: %s", name, err.Error())
+	}
+	return textResult("Deleted cookie profile %q", name)
+}
+
+// handleImportProfileCookies handles the import_profile_cookies tool call:
+// it bootstraps profile's cookie jar straight from a locally installed
+// Chrome/Edge/Firefox, so the user doesn't have to export cookies by hand.
+func (s *MCPServer) handleImportProfileCookies(ctx context.Context, args map[string]interface{}) *MCPToolResult {
+	name, _ := args["profile"].(string)
+	if name == "" {
+		return errorResult("Missing required argument \"profile\"")
+	}
+	domain, _ := args["domain"].(string)
+	if domain == "" {
+		domain = "xiaohongshu.com"
+	}
+	if s.profiles == nil {
+		return errorResult("Failed to import cookies for profile %q: no profile store configured", name)
+	}
+
+	n, err := s.profiles.ImportFromLocalBrowser(name, domain)
+	if err != nil {
+		return errorResult("Failed to import cookies for profile %q: %s", name, err.Error())
+	}
+	return textResult("Imported %d cookie(s) for domain %q into profile %q", n, domain, name)
+}
+
+// handleUploadImage handles the upload_image tool call: it pushes a
+// locally rendered image through the configured uploader.Uploader (see
+// browser.WithUploader), bypassing drag-and-drop publishing entirely.
+func (s *MCPServer) handleUploadImage(ctx context.Context, args map[string]interface{}) *MCPToolResult {
+	path, _ := args["path"].(string)
+	if path == "" {
+		return errorResult("Missing required argument \"path\"")
+	}
+
+	url, err := s.browserPool.Upload(ctx, path)
+	if err != nil {
+		return errorResult("Failed to upload %q: %s", path, err.Error())
+	}
+	return textResult("Uploaded %q to %s", path, url)
+}
+
+// handleEnsureLoggedIn handles the ensure_logged_in tool call: it checks
+// whether the active cookie profile is still authenticated against
+// creator.xiaohongshu.com and, if not, opens an interactive (non-headless)
+// login window for the caller to scan the QR code and clear any slider
+// captcha, then persists the refreshed cookies.
+func (s *MCPServer) handleEnsureLoggedIn(ctx context.Context, args map[string]interface{}) *MCPToolResult {
+	if err := s.browserPool.EnsureLoggedIn(ctx, nil); err != nil {
+		return errorResult("Login check/refresh failed for profile %q: %s", s.browserPool.Profile(), err.Error())
+	}
+	return textResult("Profile %q is authenticated", s.browserPool.Profile())
+}
+
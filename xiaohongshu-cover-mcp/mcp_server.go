@@ -11,21 +11,85 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/sirupsen/logrus"
+
+	"xiaohongshu-cover-mcp/browser"
+	"xiaohongshu-cover-mcp/cookies"
+	"xiaohongshu-cover-mcp/mcp"
 )
 
 // MCPServer MCP server structure
 type MCPServer struct {
-	browserService *BrowserService
-	router         *gin.Engine
-	httpServer     *http.Server
+	browserPool *browser.Pool
+	profiles    *cookies.ProfileStore // nil if the pool wasn't started with a cookie profile store
+	router      *gin.Engine
+	httpServer  *http.Server
+
+	rpc   *mcp.Server
+	tools map[string]*toolEntry
 }
 
-// NewMCPServer creates a new MCP server instance
-func NewMCPServer(browserService *BrowserService) *MCPServer {
-	return &MCPServer{
-		browserService: browserService,
+// NewMCPServer creates a new MCP server instance and registers the
+// initialize/tools/list/tools/call methods on its mcp.Server. profiles may
+// be nil if the server was started without -cookie-dir, in which case the
+// profile-management tools report an error instead of panicking.
+func NewMCPServer(browserPool *browser.Pool, profiles *cookies.ProfileStore) *MCPServer {
+	s := &MCPServer{
+		browserPool: browserPool,
+		profiles:    profiles,
+		rpc:         mcp.NewServer(),
 	}
+	s.tools = s.buildToolRegistry()
+	s.registerMethods()
+	return s
+}
+
+// registerMethods wires initialize/tools/list/tools/call onto s.rpc, so
+// every transport (plain HTTP, streamable-HTTP/SSE, and stdio) shares the
+// same dispatch, batching, and cancellation handling.
+func (s *MCPServer) registerMethods() {
+	s.rpc.Register("initialize", nil, func(ctx context.Context, params json.RawMessage) (interface{}, *mcp.Error) {
+		return map[string]interface{}{
+			"protocolVersion": "2025-03-26",
+			"capabilities": map[string]interface{}{
+				"tools":     map[string]interface{}{},
+				"streaming": map[string]interface{}{},
+			},
+			"serverInfo": map[string]interface{}{
+				"name":    "xiaohongshu-cover-mcp",
+				"version": "v1.0.0",
+			},
+		}, nil
+	})
+
+	s.rpc.Register("tools/list", nil, func(ctx context.Context, params json.RawMessage) (interface{}, *mcp.Error) {
+		return toolsListResult(s.tools), nil
+	})
+
+	s.rpc.Register("tools/call", nil, func(ctx context.Context, params json.RawMessage) (interface{}, *mcp.Error) {
+		call, err := parseToolCallParams(params)
+		if err != nil {
+			return nil, &mcp.Error{Code: mcp.InvalidParams, Message: "Invalid params", Data: err.Error()}
+		}
+		if call.Name == "" {
+			return nil, &mcp.Error{Code: mcp.InvalidParams, Message: "Invalid params", Data: "Missing tool name"}
+		}
+
+		tool, ok := s.tools[call.Name]
+		if !ok {
+			return nil, &mcp.Error{Code: mcp.MethodNotFound, Message: "Method not found", Data: fmt.Sprintf("Unknown tool: %s", call.Name)}
+		}
+
+		args := call.Arguments
+		if args == nil {
+			args = make(map[string]interface{})
+		}
+		if call.Meta != nil && call.Meta.ProgressToken != nil {
+			ctx = mcp.WithProgressToken(ctx, call.Meta.ProgressToken)
+		}
+		return tool.handler(ctx, args), nil
+	})
 }
 
 // Start starts the MCP server
@@ -78,10 +142,16 @@ func (s *MCPServer) setupRoutes() *gin.Engine {
 	// Health check
 	router.GET("/health", s.healthHandler)
 
-	// MCP endpoint
-	mcpHandler := s.createMCPHandler()
-	router.Any("/mcp", gin.WrapH(mcpHandler))
-	router.Any("/mcp/*path", gin.WrapH(mcpHandler))
+	// Prometheus metrics, including the browser pool's queue depth and
+	// render time quantiles
+	router.GET("/metrics", gin.WrapH(promhttp.Handler()))
+
+	// MCP endpoint, served by the shared JSON-RPC dispatcher: batching and
+	// notifications work the same here as over the stdio transport. A
+	// "$/cancelRequest" sent in a separate POST aborts an in-flight call;
+	// over stdio it is read only after the call it names has returned.
+	router.Any("/mcp", gin.WrapH(s.rpc))
+	router.Any("/mcp/*path", gin.WrapH(s.rpc))
 
 	return router
 }
@@ -109,228 +179,3 @@ func (s *MCPServer) corsMiddleware() gin.HandlerFunc {
 		c.Next()
 	}
 }
-
-// createMCPHandler creates the main MCP handler
-func (s *MCPServer) createMCPHandler() http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Content-Type", "application/json")
-
-		if r.Method != http.MethodPost {
-			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-			return
-		}
-
-		var req JSONRPCRequest
-		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-			s.sendJSONRPCError(w, req.ID, -32700, "Parse error", err.Error())
-			return
-		}
-
-		switch req.Method {
-		case "initialize":
-			s.handleInitialize(w, req)
-		case "tools/list":
-			s.handleToolsList(w, req)
-		case "tools/call":
-			s.handleToolsCall(w, req)
-		case "notifications/initialized":
-			// Client notification that initialization is complete, no response needed
-			logrus.Info("MCP: Client initialization complete")
-			return
-		case "notifications/cancelled":
-			// Client notification of cancelled request, just log it
-			logrus.Info("MCP: Received cancellation notification")
-			return
-		default:
-			s.sendJSONRPCError(w, req.ID, -32601, "Method not found", fmt.Sprintf("Unknown method: %s", req.Method))
-		}
-	})
-}
-
-// handleInitialize handles the initialize method
-func (s *MCPServer) handleInitialize(w http.ResponseWriter, req JSONRPCRequest) {
-	result := map[string]interface{}{
-		"protocolVersion": "2024-11-05",
-		"capabilities": map[string]interface{}{
-			"tools": map[string]interface{}{},
-		},
-		"serverInfo": map[string]interface{}{
-			"name":    "xiaohongshu-cover-mcp",
-			"version": "v1.0.0",
-		},
-	}
-
-	s.sendJSONRPCResponse(w, req.ID, result)
-}
-
-// handleToolsList handles the tools/list method
-func (s *MCPServer) handleToolsList(w http.ResponseWriter, req JSONRPCRequest) {
-	tools := map[string]interface{}{
-		"tools": []map[string]interface{}{
-			{
-				"name":        "generate_xiaohongshu_cover",
-				"description": "Generate a Xiaohongshu cover image with customizable text and styling",
-				"inputSchema": map[string]interface{}{
-					"type": "object",
-					"properties": map[string]interface{}{
-						"baseUrl": map[string]interface{}{
-							"type":        "string",
-							"description": "The URL to generate cover from (default: http://localhost:3000)",
-						},
-						"selector": map[string]interface{}{
-							"type":        "string",
-							"description": "CSS selector of element to screenshot (default: #exportable)",
-						},
-						"image": map[string]interface{}{
-							"type":        "string",
-							"description": "Path to the background image (default: /assets/sample1.jpg)",
-						},
-						"text": map[string]interface{}{
-							"type":        "string",
-							"description": "Text content to overlay (supports HTML, default: 'Sample Text')",
-						},
-						"output_path": map[string]interface{}{
-							"type":        "string",
-							"description": "Output file path for the generated image (default: /tmp/xiaohongshu_cover.png)",
-						},
-						"fontFamily": map[string]interface{}{
-							"type":        "string",
-							"description": "Font family name (default: 'Arial')",
-						},
-						"fontSize": map[string]interface{}{
-							"type":        "integer",
-							"description": "Font size in pixels (default: 48)",
-						},
-						"fontWeight": map[string]interface{}{
-							"type":        "string",
-							"description": "Font weight (default: 'bold')",
-						},
-						"color": map[string]interface{}{
-							"type":        "string",
-							"description": "Text color hex code (default: '#ffffff')",
-						},
-						"backgroundColor": map[string]interface{}{
-							"type":        "string",
-							"description": "Background color hex code (default: '#000000')",
-						},
-						"textShadow": map[string]interface{}{
-							"type":        "string",
-							"description": "CSS text shadow (default: '2px 2px 4px #000000')",
-						},
-						"border": map[string]interface{}{
-							"type":        "string",
-							"description": "CSS border (default: '1px solid #000000')",
-						},
-						"borderRadius": map[string]interface{}{
-							"type":        "integer",
-							"description": "Border radius in pixels (default: 0)",
-						},
-						"borderWidth": map[string]interface{}{
-							"type":        "integer",
-							"description": "Border width in pixels (default: 1)",
-						},
-						"borderStyle": map[string]interface{}{
-							"type":        "string",
-							"description": "Border style (default: 'solid')",
-						},
-						"padding": map[string]interface{}{
-							"type":        "integer",
-							"description": "Padding in pixels (default: 20)",
-						},
-						"scaleX": map[string]interface{}{
-							"type":        "number",
-							"description": "Horizontal scale (default: 1.0)",
-						},
-						"scaleY": map[string]interface{}{
-							"type":        "number",
-							"description": "Vertical scale (default: 1.0)",
-						},
-						"skewX": map[string]interface{}{
-							"type":        "number",
-							"description": "Horizontal skew in degrees (default: 0)",
-						},
-						"skewY": map[string]interface{}{
-							"type":        "number",
-							"description": "Vertical skew in degrees (default: 0)",
-						},
-						"opacity": map[string]interface{}{
-							"type":        "number",
-							"description": "Overlay opacity (0.0 to 1.0, default: 0.8)",
-						},
-						"overlayColor": map[string]interface{}{
-							"type":        "string",
-							"description": "Overlay color hex code (default: '#000000')",
-						},
-						"x": map[string]interface{}{
-							"type":        "integer",
-							"description": "Horizontal position in pixels (default: 50)",
-						},
-						"y": map[string]interface{}{
-							"type":        "integer",
-							"description": "Vertical position in pixels (default: 50)",
-						},
-					},
-					"required": []interface{}{},
-				},
-			},
-		},
-	}
-
-	s.sendJSONRPCResponse(w, req.ID, tools)
-}
-
-// handleToolsCall handles the tools/call method
-func (s *MCPServer) handleToolsCall(w http.ResponseWriter, req JSONRPCRequest) {
-	params, ok := req.Params.(map[string]interface{})
-	if !ok {
-		s.sendJSONRPCError(w, req.ID, -32602, "Invalid params", "Expected object")
-		return
-	}
-
-	toolName, ok := params["name"].(string)
-	if !ok {
-		s.sendJSONRPCError(w, req.ID, -32602, "Invalid params", "Missing tool name")
-		return
-	}
-
-	args, ok := params["arguments"].(map[string]interface{})
-	if !ok {
-		args = make(map[string]interface{})
-	}
-
-	switch toolName {
-	case "generate_xiaohongshu_cover":
-		result := s.handleGenerateXiaohongshuCover(context.Background(), args)
-		s.sendJSONRPCResponse(w, req.ID, result)
-	default:
-		s.sendJSONRPCError(w, req.ID, -32601, "Method not found", fmt.Sprintf("Unknown tool: %s", toolName))
-	}
-}
-
-// sendJSONRPCResponse sends a JSON-RPC response
-func (s *MCPServer) sendJSONRPCResponse(w http.ResponseWriter, id interface{}, result interface{}) {
-	response := JSONRPCResponse{
-		JSONRPC: "2.0",
-		Result:  result,
-		ID:      id,
-	}
-
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(response)
-}
-
-// sendJSONRPCError sends a JSON-RPC error response
-func (s *MCPServer) sendJSONRPCError(w http.ResponseWriter, id interface{}, code int, message string, data interface{}) {
-	response := JSONRPCResponse{
-		JSONRPC: "2.0",
-		Error: &JSONRPCError{
-			Code:    code,
-			Message: message,
-			Data:    data,
-		},
-		ID: id,
-	}
-
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(response)
-}
\ No newline at end of file
@@ -0,0 +1,279 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// toolEntry is one entry in the tools/list response and the tools/call
+// dispatch table: a name, its description/inputSchema (surfaced verbatim by
+// tools/list), and the handler tools/call invokes with the call's
+// "arguments" object.
+type toolEntry struct {
+	name        string
+	description string
+	inputSchema map[string]interface{}
+	handler     func(ctx context.Context, args map[string]interface{}) *MCPToolResult
+}
+
+// buildToolRegistry returns every tool this server exposes, keyed by name,
+// so tools/list and tools/call both drive off the same source of truth
+// instead of the schema and the switch-statement drifting apart.
+func (s *MCPServer) buildToolRegistry() map[string]*toolEntry {
+	tools := []*toolEntry{
+		{
+			name:        "generate_xiaohongshu_cover",
+			description: "Generate a Xiaohongshu cover image with customizable text and styling",
+			inputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"baseUrl": map[string]interface{}{
+						"type":        "string",
+						"description": "The URL to generate cover from (default: http://localhost:3000)",
+					},
+					"selector": map[string]interface{}{
+						"type":        "string",
+						"description": "CSS selector of element to screenshot (default: #exportable)",
+					},
+					"image": map[string]interface{}{
+						"type":        "string",
+						"description": "Path to the background image (default: /assets/6.jpg)",
+					},
+					"text": map[string]interface{}{
+						"type":        "string",
+						"description": "Text content to overlay (supports HTML, default: a sample visitor-count line)",
+					},
+					"output_path": map[string]interface{}{
+						"type":        "string",
+						"description": "Output file path for the generated image (default: /tmp/xiaohongshu_cover.png)",
+					},
+					"fontFamily": map[string]interface{}{
+						"type":        "string",
+						"description": "Font family name (default: 'Arial')",
+					},
+					"fontSize": map[string]interface{}{
+						"type":        "integer",
+						"description": "Font size in pixels (default: 48)",
+					},
+					"fontWeight": map[string]interface{}{
+						"type":        "string",
+						"description": "Font weight (default: 'bold')",
+					},
+					"color": map[string]interface{}{
+						"type":        "string",
+						"description": "Text color hex code (default: '#0e0d0c')",
+					},
+					"backgroundColor": map[string]interface{}{
+						"type":        "string",
+						"description": "Background color hex code (default: '#f4f750')",
+					},
+					"textShadow": map[string]interface{}{
+						"type":        "string",
+						"description": "CSS text shadow (default: '2px 2px 4px #000000')",
+					},
+					"border": map[string]interface{}{
+						"type":        "string",
+						"description": "CSS border (default: '1px solid #000000')",
+					},
+					"borderRadius": map[string]interface{}{
+						"type":        "integer",
+						"description": "Border radius in pixels (default: 32)",
+					},
+					"borderWidth": map[string]interface{}{
+						"type":        "integer",
+						"description": "Border width in pixels (default: 3)",
+					},
+					"borderStyle": map[string]interface{}{
+						"type":        "string",
+						"description": "Border style (default: 'dashed')",
+					},
+					"padding": map[string]interface{}{
+						"type":        "integer",
+						"description": "Padding in pixels (default: 20)",
+					},
+					"scaleX": map[string]interface{}{
+						"type":        "number",
+						"description": "Horizontal scale (default: 1.0)",
+					},
+					"scaleY": map[string]interface{}{
+						"type":        "number",
+						"description": "Vertical scale (default: 1.0)",
+					},
+					"skewX": map[string]interface{}{
+						"type":        "number",
+						"description": "Horizontal skew in degrees (default: 0)",
+					},
+					"skewY": map[string]interface{}{
+						"type":        "number",
+						"description": "Vertical skew in degrees (default: 0)",
+					},
+					"opacity": map[string]interface{}{
+						"type":        "number",
+						"description": "Overlay opacity (0.0 to 1.0, default: 0.8)",
+					},
+					"overlayColor": map[string]interface{}{
+						"type":        "string",
+						"description": "Overlay color hex code (default: '#443c3c')",
+					},
+					"x": map[string]interface{}{
+						"type":        "integer",
+						"description": "Horizontal position in pixels (default: 50)",
+					},
+					"y": map[string]interface{}{
+						"type":        "integer",
+						"description": "Vertical position in pixels (default: 50)",
+					},
+					"overlayImage": map[string]interface{}{
+						"type":        "string",
+						"description": "Optional data URI (e.g. 'data:image/png;base64,...') of an extra image, such as a trend chart, drawn on top of the cover",
+					},
+				},
+				"required": []interface{}{},
+			},
+			handler: s.handleGenerateXiaohongshuCover,
+		},
+		{
+			name:        "generate_xiaohongshu_covers_batch",
+			description: "Generate multiple Xiaohongshu cover images concurrently using the warm browser pool",
+			inputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"covers": map[string]interface{}{
+						"type":        "array",
+						"description": "Array of argument objects, one per cover, with the same fields as generate_xiaohongshu_cover",
+					},
+					"concurrency": map[string]interface{}{
+						"type":        "integer",
+						"description": "Maximum number of covers to render at once (default: the number of covers; renders beyond the pool size wait for a free tab)",
+					},
+				},
+				"required": []interface{}{"covers"},
+			},
+			handler: s.handleGenerateXiaohongshuCoversBatch,
+		},
+		{
+			name:        "list_cookie_profiles",
+			description: "List saved Xiaohongshu cookie profiles and report which one is currently active",
+			inputSchema: map[string]interface{}{
+				"type":       "object",
+				"properties": map[string]interface{}{},
+				"required":   []interface{}{},
+			},
+			handler: s.handleListProfiles,
+		},
+		{
+			name:        "switch_cookie_profile",
+			description: "Switch the browser pool's active Xiaohongshu cookie profile, reloading every pooled tab with that profile's cookies",
+			inputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"profile": map[string]interface{}{
+						"type":        "string",
+						"description": "Name of the cookie profile to switch to",
+					},
+				},
+				"required": []interface{}{"profile"},
+			},
+			handler: s.handleSwitchProfile,
+		},
+		{
+			name:        "delete_cookie_profile",
+			description: "Delete a saved Xiaohongshu cookie profile; fails if it's the currently active profile",
+			inputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"profile": map[string]interface{}{
+						"type":        "string",
+						"description": "Name of the cookie profile to delete",
+					},
+				},
+				"required": []interface{}{"profile"},
+			},
+			handler: s.handleDeleteProfile,
+		},
+		{
+			name:        "import_profile_cookies",
+			description: "Import Xiaohongshu cookies from a locally installed Chrome/Edge/Firefox into a named cookie profile",
+			inputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"profile": map[string]interface{}{
+						"type":        "string",
+						"description": "Name of the cookie profile to import cookies into",
+					},
+					"domain": map[string]interface{}{
+						"type":        "string",
+						"description": "Cookie domain to match (default: xiaohongshu.com)",
+					},
+				},
+				"required": []interface{}{"profile"},
+			},
+			handler: s.handleImportProfileCookies,
+		},
+		{
+			name:        "ensure_logged_in",
+			description: "Verify the active cookie profile is still authenticated against creator.xiaohongshu.com, opening an interactive QR-code login window if it isn't",
+			inputSchema: map[string]interface{}{
+				"type":       "object",
+				"properties": map[string]interface{}{},
+				"required":   []interface{}{},
+			},
+			handler: s.handleEnsureLoggedIn,
+		},
+		{
+			name:        "upload_image",
+			description: "Upload a locally rendered image via the configured upload backend, bypassing drag-and-drop publishing",
+			inputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"path": map[string]interface{}{
+						"type":        "string",
+						"description": "Path to the local image file to upload",
+					},
+				},
+				"required": []interface{}{"path"},
+			},
+			handler: s.handleUploadImage,
+		},
+	}
+
+	registry := make(map[string]*toolEntry, len(tools))
+	for _, t := range tools {
+		registry[t.name] = t
+	}
+	return registry
+}
+
+// toolsListResult is the tools/list response body, rendered from the tool
+// registry instead of hand-duplicated.
+func toolsListResult(tools map[string]*toolEntry) map[string]interface{} {
+	list := make([]map[string]interface{}, 0, len(tools))
+	for _, t := range tools {
+		list = append(list, map[string]interface{}{
+			"name":        t.name,
+			"description": t.description,
+			"inputSchema": t.inputSchema,
+		})
+	}
+	return map[string]interface{}{"tools": list}
+}
+
+// toolCallParams is the "params" object of a tools/call request. Meta
+// carries the client's optional progressToken, asking the server to stream
+// back "notifications/progress" events for this call.
+type toolCallParams struct {
+	Name      string                 `json:"name"`
+	Arguments map[string]interface{} `json:"arguments"`
+	Meta      *struct {
+		ProgressToken interface{} `json:"progressToken"`
+	} `json:"_meta,omitempty"`
+}
+
+func parseToolCallParams(raw json.RawMessage) (toolCallParams, error) {
+	var p toolCallParams
+	if len(raw) == 0 {
+		return p, nil
+	}
+	err := json.Unmarshal(raw, &p)
+	return p, err
+}
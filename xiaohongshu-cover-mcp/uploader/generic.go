@@ -0,0 +1,83 @@
+package uploader
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// GenericUploader streams a file to any multipart-form-accepting image
+// host - an S3-compatible bucket's presigned form endpoint, an imgur-style
+// API, an internal asset service - configured entirely by URL/field/auth
+// instead of a bespoke client per host.
+type GenericUploader struct {
+	URL  string // endpoint to POST the multipart form to
+	Field string // multipart form field name the file is attached under
+
+	// AuthHeader is a raw "Name: value" header, e.g. "Authorization: Bearer
+	// xyz"; empty means no auth header is sent.
+	AuthHeader string
+
+	HTTPClient *http.Client
+}
+
+// NewGenericUploader builds a GenericUploader for a third-party image host.
+func NewGenericUploader(url, field, authHeader string) *GenericUploader {
+	return &GenericUploader{URL: url, Field: field, AuthHeader: authHeader, HTTPClient: &http.Client{}}
+}
+
+// Upload streams imgPath into a multipart/form-data body under Field and
+// POSTs it to URL, returning the response body (trimmed) as-is - most
+// third-party hosts just return the hosted URL as plain text or a bare JSON
+// string, and anything more structured is on the caller to parse.
+func (u *GenericUploader) Upload(ctx context.Context, imgPath string) (string, error) {
+	f, err := os.Open(imgPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to open %q: %w", imgPath, err)
+	}
+	defer f.Close()
+
+	pr, pw := io.Pipe()
+	mw := multipart.NewWriter(pw)
+	go func() {
+		pw.CloseWithError(func() error {
+			part, err := mw.CreateFormFile(u.Field, filepath.Base(imgPath))
+			if err != nil {
+				return err
+			}
+			if _, err := io.Copy(part, f); err != nil {
+				return fmt.Errorf("failed to stream %q into request body: %w", imgPath, err)
+			}
+			return mw.Close()
+		}())
+	}()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u.URL, pr)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	if name, value, ok := strings.Cut(u.AuthHeader, ": "); ok {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := u.HTTPClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("upload request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read upload response: %w", err)
+	}
+	if resp.StatusCode/100 != 2 {
+		return "", fmt.Errorf("upload failed with status %s: %s", resp.Status, strings.TrimSpace(string(data)))
+	}
+	return strings.TrimSpace(string(data)), nil
+}
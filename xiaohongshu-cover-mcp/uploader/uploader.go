@@ -0,0 +1,14 @@
+// Package uploader provides pluggable backends for getting a locally
+// rendered image somewhere fetchable, as an alternative to driving
+// Xiaohongshu's own drag-and-drop page uploader through chromedp - useful
+// when publishing covers in bulk, where the DOM round-trip per image
+// dominates batch latency.
+package uploader
+
+import "context"
+
+// Uploader pushes the file at imgPath somewhere reachable and returns its
+// URL.
+type Uploader interface {
+	Upload(ctx context.Context, imgPath string) (string, error)
+}
@@ -0,0 +1,98 @@
+package uploader
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/chromedp/cdproto/network"
+)
+
+// xhsUploadURL is Xiaohongshu's internal creator image upload endpoint.
+const xhsUploadURL = "https://creator.xiaohongshu.com/api/media/upload"
+
+// XHSUploader POSTs directly to Xiaohongshu's internal creator image
+// endpoint, authenticating with cookies lifted from an already-logged-in
+// browser session instead of driving the page's drag-and-drop uploader.
+type XHSUploader struct {
+	HTTPClient *http.Client
+	Cookies    []*network.CookieParam
+}
+
+// NewXHSUploader builds an XHSUploader that authenticates with cookies
+// (typically the active profile's cookies, as saved by cookies.ProfileStore).
+func NewXHSUploader(cookies []*network.CookieParam) *XHSUploader {
+	return &XHSUploader{HTTPClient: &http.Client{}, Cookies: cookies}
+}
+
+// Upload POSTs imgPath to xhsUploadURL as a multipart/form-data body, built
+// in memory, and returns the hosted URL Xiaohongshu assigns it.
+func (u *XHSUploader) Upload(ctx context.Context, imgPath string) (string, error) {
+	f, err := os.Open(imgPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to open %q: %w", imgPath, err)
+	}
+	defer f.Close()
+
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	part, err := mw.CreateFormFile("file", filepath.Base(imgPath))
+	if err != nil {
+		return "", fmt.Errorf("failed to build multipart body: %w", err)
+	}
+	if _, err := io.Copy(part, f); err != nil {
+		return "", fmt.Errorf("failed to stream %q into request body: %w", imgPath, err)
+	}
+	if err := mw.Close(); err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, xhsUploadURL, &body)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	req.Header.Set("Cookie", cookieHeader(u.Cookies))
+
+	resp, err := u.HTTPClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("upload request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("upload failed with status %s", resp.Status)
+	}
+
+	var result struct {
+		URL string `json:"url"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("failed to parse upload response: %w", err)
+	}
+	if result.URL == "" {
+		return "", fmt.Errorf("upload response did not include a url")
+	}
+	return result.URL, nil
+}
+
+// cookieHeader renders cookies as a single "Cookie:" header value.
+func cookieHeader(cookies []*network.CookieParam) string {
+	var b strings.Builder
+	for i, c := range cookies {
+		if i > 0 {
+			b.WriteString("; ")
+		}
+		b.WriteString(c.Name)
+		b.WriteString("=")
+		b.WriteString(c.Value)
+	}
+	return b.String()
+}
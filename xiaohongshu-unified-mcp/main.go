@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"os"
@@ -18,12 +19,26 @@ func main() {
 	// 解析命令行参数
 	headless := flag.Bool("headless", true, "Run browser in headless mode")
 	port := flag.String("port", ":18060", "Server port")
+	pprofEnabled := flag.Bool("pprof", false, "Expose pprof debug endpoints under /debug/pprof")
+	rateLimitRPS := flag.Float64("rate-limit-rps", 5.0, "Per-IP and per-tool requests-per-second limit")
+	rateLimitBurst := flag.Int("rate-limit-burst", 10, "Per-IP and per-tool rate limit burst size")
+	otlpEndpoint := flag.String("otlp-endpoint", "", "OTLP gRPC endpoint to export traces to (e.g. Jaeger's localhost:4317); empty disables tracing")
 	flag.Parse()
 
 	logrus.Infof("Starting Xiaohongshu Unified MCP Server...")
 	logrus.Infof("Headless mode: %v", *headless)
 	logrus.Infof("Port: %s", *port)
 
+	shutdownTracing, err := initTracing(context.Background(), *otlpEndpoint)
+	if err != nil {
+		logrus.Warnf("Failed to initialize tracing, continuing without it: %v", err)
+	}
+	defer func() {
+		if err := shutdownTracing(context.Background()); err != nil {
+			logrus.Warnf("Failed to flush trace provider: %v", err)
+		}
+	}()
+
 	// 初始化浏览器服务
 	browserService := NewBrowserService(*headless)
 	if browserService == nil {
@@ -40,6 +55,8 @@ func main() {
 
 	// 创建统一MCP服务器
 	mcpServer := NewUnifiedMCPServer(browserService, xiaohongshuService)
+	mcpServer.SetRateLimit(*rateLimitRPS, *rateLimitBurst)
+	mcpServer.SetPprofEnabled(*pprofEnabled)
 
 	// 启动服务器
 	logrus.Info("Available tools:")
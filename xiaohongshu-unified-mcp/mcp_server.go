@@ -1,33 +1,71 @@
 package main
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"os"
 	"os/signal"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
 	"syscall"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
 )
 
+// tracer emits one span per incoming JSON-RPC call. createMCPHandler
+// extracts any trace context the caller propagated in the request headers,
+// so these spans join the caller's trace instead of starting a disconnected
+// one. xhs.MCPPublisher sends no trace headers, so its calls start a new
+// trace here.
+var tracer = otel.Tracer("xiaohongshu-unified-mcp")
+
 // UnifiedMCPServer 统一MCP服务器结构体
 type UnifiedMCPServer struct {
 	browserService     *BrowserService
 	xiaohongshuService *XiaohongshuService
 	router             *gin.Engine
 	httpServer         *http.Server
+	tools              map[string]*registeredTool
+
+	// cancelMu guards cancelFns, which lets notifications/cancelled abort
+	// an in-flight SSE-streamed tool call by request ID.
+	cancelMu  sync.Mutex
+	cancelFns map[interface{}]context.CancelFunc
+
+	// ipLimiter and toolLimiter enforce per-IP and per-tool token-bucket
+	// rate limits; see SetRateLimit. pprofEnabled gates /debug/pprof, and
+	// onPanicNotify is the pluggable alerting hook for recoveryMiddleware.
+	ipLimiter     *rateLimiter
+	toolLimiter   *rateLimiter
+	pprofEnabled  bool
+	onPanicNotify func(ctx context.Context, err interface{}, stack []byte)
 }
 
 // NewUnifiedMCPServer 创建新的统一MCP服务器实例
 func NewUnifiedMCPServer(browserService *BrowserService, xiaohongshuService *XiaohongshuService) *UnifiedMCPServer {
-	return &UnifiedMCPServer{
+	s := &UnifiedMCPServer{
 		browserService:     browserService,
 		xiaohongshuService: xiaohongshuService,
+		cancelFns:          make(map[interface{}]context.CancelFunc),
+		ipLimiter:          newRateLimiter(5, 10),
+		toolLimiter:        newRateLimiter(5, 10),
 	}
+	s.tools = buildToolRegistry(s)
+	return s
 }
 
 // Start 启动MCP服务器
@@ -74,12 +112,21 @@ func (s *UnifiedMCPServer) setupRoutes() *gin.Engine {
 
 	router := gin.New()
 	router.Use(gin.Logger())
-	router.Use(gin.Recovery())
+	router.Use(s.recoveryMiddleware())
 	router.Use(s.corsMiddleware())
+	router.Use(metricsMiddleware())
+	router.Use(s.ipRateLimitMiddleware())
 
 	// 健康检查
 	router.GET("/health", s.healthHandler)
 
+	// Prometheus指标
+	router.GET("/metrics", gin.WrapH(promhttp.Handler()))
+
+	if s.pprofEnabled {
+		registerPprof(router)
+	}
+
 	// MCP端点
 	mcpHandler := s.createMCPHandler()
 	router.Any("/mcp", gin.WrapH(mcpHandler))
@@ -120,46 +167,243 @@ func (s *UnifiedMCPServer) corsMiddleware() gin.HandlerFunc {
 }
 
 // createMCPHandler 创建MCP处理器
+//
+// Supports three request shapes: a single JSON-RPC object (the classic
+// path), a JSON-RPC batch array (each entry dispatched independently, with
+// an array of responses returned), and - for a single "tools/call" request
+// sent with `Accept: text/event-stream` - an SSE stream of
+// "notifications/progress" events followed by the final JSON-RPC response.
 func (s *UnifiedMCPServer) createMCPHandler() http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Content-Type", "application/json")
-
 		if r.Method != http.MethodPost {
 			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 			return
 		}
 
+		ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+		r = r.WithContext(ctx)
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			s.writeJSON(w, s.buildJSONRPCError(nil, -32700, "Parse error", err.Error()))
+			return
+		}
+
+		trimmed := bytes.TrimSpace(body)
+		if len(trimmed) > 0 && trimmed[0] == '[' {
+			s.handleBatch(r.Context(), w, trimmed)
+			return
+		}
+
 		var req JSONRPCRequest
-		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-			s.sendJSONRPCError(w, req.ID, -32700, "Parse error", err.Error())
+		if err := json.Unmarshal(trimmed, &req); err != nil {
+			s.writeJSON(w, s.buildJSONRPCError(nil, -32700, "Parse error", err.Error()))
 			return
 		}
 
 		logrus.Infof("Received MCP request: method=%s, id=%v", req.Method, req.ID)
 
-		switch req.Method {
-		case "initialize":
-			s.handleInitialize(w, req)
-		case "tools/list":
-			s.handleToolsList(w, req)
-		case "tools/call":
-			s.handleToolsCall(w, req, r.Context())
-		case "notifications/initialized":
-			// Client notification that initialization is complete, no response needed
-			logrus.Info("MCP: Client initialization complete")
-			return
-		case "notifications/cancelled":
-			// Client notification of cancelled request, just log it
-			logrus.Info("MCP: Received cancellation notification")
+		ctx, span := tracer.Start(r.Context(), "mcp."+req.Method, trace.WithAttributes(
+			attribute.String("rpc.jsonrpc.method", req.Method),
+		))
+		defer span.End()
+
+		if req.Method == "tools/call" && strings.Contains(r.Header.Get("Accept"), "text/event-stream") {
+			s.handleToolsCallStream(w, r.WithContext(ctx), req)
 			return
-		default:
-			s.sendJSONRPCError(w, req.ID, -32601, "Method not found", fmt.Sprintf("Unknown method: %s", req.Method))
+		}
+
+		if resp := s.dispatch(ctx, req, nil); resp != nil {
+			if resp.Error != nil {
+				span.SetStatus(codes.Error, resp.Error.Message)
+			}
+			s.writeJSON(w, resp)
 		}
 	})
 }
 
-// handleInitialize 处理初始化请求
-func (s *UnifiedMCPServer) handleInitialize(w http.ResponseWriter, req JSONRPCRequest) {
+// handleBatch decodes a JSON-RPC batch array, dispatches each request, and
+// returns the array of responses (notifications that produce no response
+// are simply omitted, per the JSON-RPC 2.0 spec).
+func (s *UnifiedMCPServer) handleBatch(ctx context.Context, w http.ResponseWriter, body []byte) {
+	var reqs []JSONRPCRequest
+	if err := json.Unmarshal(body, &reqs); err != nil {
+		s.writeJSON(w, s.buildJSONRPCError(nil, -32700, "Parse error", err.Error()))
+		return
+	}
+
+	responses := make([]JSONRPCResponse, 0, len(reqs))
+	for _, req := range reqs {
+		logrus.Infof("Received batched MCP request: method=%s, id=%v", req.Method, req.ID)
+
+		reqCtx, span := tracer.Start(ctx, "mcp."+req.Method, trace.WithAttributes(
+			attribute.String("rpc.jsonrpc.method", req.Method),
+			attribute.Bool("rpc.jsonrpc.batched", true),
+		))
+		resp := s.dispatch(reqCtx, req, nil)
+		if resp != nil {
+			if resp.Error != nil {
+				span.SetStatus(codes.Error, resp.Error.Message)
+			}
+			responses = append(responses, *resp)
+		}
+		span.End()
+	}
+
+	s.writeJSON(w, responses)
+}
+
+// handleToolsCallStream upgrades a single "tools/call" request to
+// text/event-stream, relaying progress events pushed onto a channel
+// threaded through the tool handler's context as
+// "notifications/progress" SSE frames, then emits the final JSON-RPC
+// response as a last event. notifications/cancelled can abort the call
+// mid-flight via the request's ID.
+func (s *UnifiedMCPServer) handleToolsCallStream(w http.ResponseWriter, r *http.Request, req JSONRPCRequest) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		if resp := s.dispatch(r.Context(), req, nil); resp != nil {
+			s.writeJSON(w, resp)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+	s.registerCancellable(req.ID, cancel)
+	defer s.unregisterCancellable(req.ID)
+
+	progress := make(chan ProgressEvent, 8)
+	resultCh := make(chan *JSONRPCResponse, 1)
+
+	go func() {
+		resp := s.dispatch(ctx, req, progress)
+		close(progress)
+		resultCh <- resp
+	}()
+
+	for {
+		select {
+		case evt, open := <-progress:
+			if !open {
+				progress = nil
+				continue
+			}
+			s.writeSSEEvent(w, flusher, "notifications/progress", map[string]interface{}{
+				"jsonrpc": "2.0",
+				"method":  "notifications/progress",
+				"params": map[string]interface{}{
+					"requestId": req.ID,
+					"message":   evt.Message,
+					"percent":   evt.Percent,
+				},
+			})
+		case resp := <-resultCh:
+			if resp != nil {
+				s.writeSSEEvent(w, flusher, "message", resp)
+			}
+			return
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// writeSSEEvent writes one framed SSE event and flushes it immediately.
+func (s *UnifiedMCPServer) writeSSEEvent(w http.ResponseWriter, flusher http.Flusher, event string, payload interface{}) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		logrus.Errorf("Failed to marshal SSE payload: %v", err)
+		return
+	}
+	fmt.Fprintf(w, "event: %s\n", event)
+	fmt.Fprintf(w, "data: %s\n\n", data)
+	flusher.Flush()
+}
+
+// writeJSON writes a plain JSON-RPC response (or batch of responses).
+func (s *UnifiedMCPServer) writeJSON(w http.ResponseWriter, payload interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(payload)
+}
+
+// registerCancellable tracks cancel so a later notifications/cancelled for
+// the same request ID can abort the in-flight tool call.
+func (s *UnifiedMCPServer) registerCancellable(id interface{}, cancel context.CancelFunc) {
+	if id == nil {
+		return
+	}
+	s.cancelMu.Lock()
+	defer s.cancelMu.Unlock()
+	s.cancelFns[id] = cancel
+}
+
+// unregisterCancellable removes the tracked cancel func once the call has
+// finished, cancelled or not.
+func (s *UnifiedMCPServer) unregisterCancellable(id interface{}) {
+	if id == nil {
+		return
+	}
+	s.cancelMu.Lock()
+	defer s.cancelMu.Unlock()
+	delete(s.cancelFns, id)
+}
+
+// dispatch routes a single JSON-RPC request to its handler. progress may be
+// nil; when non-nil it's threaded into the tool handler's context so long
+// running tools can report "notifications/progress" events. Returns nil
+// for notifications that expect no response.
+func (s *UnifiedMCPServer) dispatch(ctx context.Context, req JSONRPCRequest, progress chan<- ProgressEvent) *JSONRPCResponse {
+	switch req.Method {
+	case "initialize":
+		return s.buildInitializeResponse(req)
+	case "tools/list":
+		return s.buildToolsListResponse(req)
+	case "tools/call":
+		if progress != nil {
+			ctx = withProgressChannel(ctx, progress)
+		}
+		return s.buildToolsCallResponse(ctx, req)
+	case "notifications/initialized":
+		// Client notification that initialization is complete, no response needed
+		logrus.Info("MCP: Client initialization complete")
+		return nil
+	case "notifications/cancelled":
+		s.handleCancelledNotification(req)
+		return nil
+	default:
+		return s.buildJSONRPCError(req.ID, -32601, "Method not found", fmt.Sprintf("Unknown method: %s", req.Method))
+	}
+}
+
+// handleCancelledNotification looks up the cancel func for the request ID
+// named in the notification's params and invokes it, aborting the
+// corresponding in-flight SSE-streamed tool call.
+func (s *UnifiedMCPServer) handleCancelledNotification(req JSONRPCRequest) {
+	params, _ := req.Params.(map[string]interface{})
+	requestID := params["requestId"]
+
+	s.cancelMu.Lock()
+	cancel, ok := s.cancelFns[requestID]
+	s.cancelMu.Unlock()
+
+	if !ok {
+		logrus.Infof("MCP: received cancellation for unknown or completed request %v", requestID)
+		return
+	}
+
+	logrus.Infof("MCP: cancelling in-flight request %v", requestID)
+	cancel()
+}
+
+// buildInitializeResponse 处理初始化请求
+func (s *UnifiedMCPServer) buildInitializeResponse(req JSONRPCRequest) *JSONRPCResponse {
 	result := map[string]interface{}{
 		"protocolVersion": "2024-11-05",
 		"capabilities": map[string]interface{}{
@@ -171,139 +415,89 @@ func (s *UnifiedMCPServer) handleInitialize(w http.ResponseWriter, req JSONRPCRe
 		},
 	}
 
-	s.sendJSONRPCResponse(w, req.ID, result)
+	return s.buildJSONRPCResponse(req.ID, result)
 }
 
-// handleToolsList 处理工具列表请求
-func (s *UnifiedMCPServer) handleToolsList(w http.ResponseWriter, req JSONRPCRequest) {
-	tools := []map[string]interface{}{
-		{
-			"name":        "generate_xiaohongshu_cover",
-			"description": "Generate Xiaohongshu cover image with customizable parameters",
-			"inputSchema": map[string]interface{}{
-				"type": "object",
-				"properties": map[string]interface{}{
-					"baseUrl":         map[string]string{"type": "string", "description": "Base URL for the cover generator"},
-					"selector":        map[string]string{"type": "string", "description": "CSS selector for screenshot"},
-					"image":           map[string]string{"type": "string", "description": "Background image path"},
-					"text":            map[string]string{"type": "string", "description": "Text content to display"},
-					"output_path":     map[string]string{"type": "string", "description": "Output file path"},
-					"fontFamily":      map[string]string{"type": "string", "description": "Font family"},
-					"fontSize":        map[string]string{"type": "number", "description": "Font size"},
-					"color":           map[string]string{"type": "string", "description": "Text color"},
-					"backgroundColor": map[string]string{"type": "string", "description": "Background color"},
-				},
-			},
-		},
-		{
-			"name":        "check_login_status",
-			"description": "Check Xiaohongshu login status",
-			"inputSchema": map[string]interface{}{
-				"type":       "object",
-				"properties": map[string]interface{}{},
-			},
-		},
-		{
-			"name":        "publish_content",
-			"description": "Publish content to Xiaohongshu",
-			"inputSchema": map[string]interface{}{
-				"type": "object",
-				"properties": map[string]interface{}{
-					"title":   map[string]string{"type": "string", "description": "Content title"},
-					"content": map[string]string{"type": "string", "description": "Content body"},
-					"images":  map[string]interface{}{"type": "array", "items": map[string]string{"type": "string"}, "description": "Image paths"},
-				},
-				"required": []string{"title", "content", "images"},
-			},
-		},
-		{
-			"name":        "list_feeds",
-			"description": "List Xiaohongshu feeds",
-			"inputSchema": map[string]interface{}{
-				"type":       "object",
-				"properties": map[string]interface{}{},
-			},
-		},
-		{
-			"name":        "search_feeds",
-			"description": "Search Xiaohongshu feeds by keyword",
-			"inputSchema": map[string]interface{}{
-				"type": "object",
-				"properties": map[string]interface{}{
-					"keyword": map[string]string{"type": "string", "description": "Search keyword"},
-					"limit":   map[string]string{"type": "number", "description": "Maximum number of results"},
-				},
-				"required": []string{"keyword"},
-			},
-		},
+// buildToolsListResponse 处理工具列表请求. Each tool's inputSchema is
+// reflected from its argument struct by registerTool, so this never drifts
+// out of sync with the handlers the way the hand-maintained schema used to.
+func (s *UnifiedMCPServer) buildToolsListResponse(req JSONRPCRequest) *JSONRPCResponse {
+	names := make([]string, 0, len(s.tools))
+	for name := range s.tools {
+		names = append(names, name)
 	}
-
-	response := JSONRPCResponse{
-		JSONRPC: "2.0",
-		Result:  map[string]interface{}{"tools": tools},
-		ID:      req.ID,
+	sort.Strings(names)
+
+	tools := make([]map[string]interface{}, 0, len(names))
+	for _, name := range names {
+		tool := s.tools[name]
+		tools = append(tools, map[string]interface{}{
+			"name":        tool.name,
+			"description": tool.description,
+			"inputSchema": tool.inputSchema,
+		})
 	}
 
-	json.NewEncoder(w).Encode(response)
+	return s.buildJSONRPCResponse(req.ID, map[string]interface{}{"tools": tools})
 }
 
-// handleToolsCall 处理工具调用请求
-func (s *UnifiedMCPServer) handleToolsCall(w http.ResponseWriter, req JSONRPCRequest, ctx context.Context) {
+// buildToolsCallResponse 处理工具调用请求. Long-running tools read the
+// progress channel threaded into ctx (see withProgressChannel) to emit
+// notifications/progress events while a call is streamed over SSE, and
+// should check ctx.Done() periodically so notifications/cancelled can
+// actually abort them.
+//
+// Argument unmarshalling and validation happen inside the registeredTool's
+// call func (see registerTool), so a missing or malformed "arguments"
+// object never panics here - it comes back as a -32602 Invalid params
+// error instead, whose data is a per-field map when validation fails and
+// the decode error's text when unmarshalling fails.
+func (s *UnifiedMCPServer) buildToolsCallResponse(ctx context.Context, req JSONRPCRequest) *JSONRPCResponse {
 	params, ok := req.Params.(map[string]interface{})
 	if !ok {
-		s.sendJSONRPCError(w, req.ID, -32602, "Invalid params", "Params must be an object")
-		return
+		return s.buildJSONRPCError(req.ID, -32602, "Invalid params", "Params must be an object")
 	}
 
-	toolCall := MCPToolCall{
-		Name:      params["name"].(string),
-		Arguments: params["arguments"].(map[string]interface{}),
+	name, _ := params["name"].(string)
+	tool, ok := s.tools[name]
+	if !ok {
+		return s.buildJSONRPCError(req.ID, -32601, "Method not found", fmt.Sprintf("Unknown tool: %s", name))
 	}
 
-	logrus.Infof("Executing tool: %s", toolCall.Name)
-
-	var result *MCPToolResult
-
-	switch toolCall.Name {
-	case "generate_xiaohongshu_cover":
-		result = s.handleGenerateXiaohongshuCover(ctx, toolCall.Arguments)
-	case "check_login_status":
-		result = s.handleCheckLoginStatus(ctx, toolCall.Arguments)
-	case "publish_content":
-		result = s.handlePublishContent(ctx, toolCall.Arguments)
-	case "list_feeds":
-		result = s.handleListFeeds(ctx, toolCall.Arguments)
-	case "search_feeds":
-		result = s.handleSearchFeeds(ctx, toolCall.Arguments)
-	default:
-		s.sendJSONRPCError(w, req.ID, -32601, "Method not found", fmt.Sprintf("Unknown tool: %s", toolCall.Name))
-		return
+	if !s.toolLimiter.Allow(name) {
+		return s.buildJSONRPCError(req.ID, -32000, "Rate limit exceeded", fmt.Sprintf("tool %q is being called too frequently", name))
 	}
 
-	response := JSONRPCResponse{
-		JSONRPC: "2.0",
-		Result:  result,
-		ID:      req.ID,
+	rawArgs, _ := params["arguments"].(map[string]interface{})
+	if rawArgs == nil {
+		rawArgs = map[string]interface{}{}
 	}
 
-	json.NewEncoder(w).Encode(response)
+	logrus.Infof("Executing tool: %s", name)
+
+	start := time.Now()
+	result, rpcErr := tool.call(ctx, rawArgs)
+	toolCallDuration.WithLabelValues(name).Observe(time.Since(start).Seconds())
+	toolCallsTotal.WithLabelValues(name, strconv.FormatBool(rpcErr == nil)).Inc()
+
+	if rpcErr != nil {
+		return &JSONRPCResponse{JSONRPC: "2.0", Error: rpcErr, ID: req.ID}
+	}
+	return s.buildJSONRPCResponse(req.ID, result)
 }
 
-// sendJSONRPCResponse 发送JSON-RPC响应
-func (s *UnifiedMCPServer) sendJSONRPCResponse(w http.ResponseWriter, id interface{}, result interface{}) {
-	response := JSONRPCResponse{
+// buildJSONRPCResponse 构造JSON-RPC响应
+func (s *UnifiedMCPServer) buildJSONRPCResponse(id interface{}, result interface{}) *JSONRPCResponse {
+	return &JSONRPCResponse{
 		JSONRPC: "2.0",
 		Result:  result,
 		ID:      id,
 	}
-
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(response)
 }
 
-// sendJSONRPCError 发送JSON-RPC错误响应
-func (s *UnifiedMCPServer) sendJSONRPCError(w http.ResponseWriter, id interface{}, code int, message, data string) {
-	errorResp := JSONRPCResponse{
+// buildJSONRPCError 构造JSON-RPC错误响应
+func (s *UnifiedMCPServer) buildJSONRPCError(id interface{}, code int, message, data string) *JSONRPCResponse {
+	return &JSONRPCResponse{
 		JSONRPC: "2.0",
 		Error: &JSONRPCError{
 			Code:    code,
@@ -312,7 +506,4 @@ func (s *UnifiedMCPServer) sendJSONRPCError(w http.ResponseWriter, id interface{
 		},
 		ID: id,
 	}
-
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(errorResp)
-}
\ No newline at end of file
+}
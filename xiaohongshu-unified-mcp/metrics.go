@@ -0,0 +1,27 @@
+package main
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	// toolCallsTotal counts every tools/call invocation, labeled by tool
+	// name and whether it succeeded.
+	toolCallsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "mcp_tool_calls_total",
+		Help: "Total number of MCP tool calls, labeled by tool and success.",
+	}, []string{"tool", "success"})
+
+	// toolCallDuration tracks tools/call latency, labeled by tool name.
+	toolCallDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name: "mcp_tool_duration_seconds",
+		Help: "MCP tool call latency in seconds, labeled by tool.",
+	}, []string{"tool"})
+
+	// httpRequestsTotal counts every HTTP request the server handles.
+	httpRequestsTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "mcp_http_requests_total",
+		Help: "Total number of HTTP requests handled by the MCP server.",
+	})
+)
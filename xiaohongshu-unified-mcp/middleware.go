@@ -0,0 +1,83 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	_ "net/http/pprof" // registers debug handlers on http.DefaultServeMux
+	"runtime/debug"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// ipRateLimitMiddleware enforces a per-client-IP token bucket, responding
+// with a JSON-RPC -32000 error instead of dispatching the request when the
+// bucket is empty.
+func (s *UnifiedMCPServer) ipRateLimitMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !s.ipLimiter.Allow(c.ClientIP()) {
+			c.JSON(http.StatusTooManyRequests, s.buildJSONRPCError(nil, -32000, "Rate limit exceeded", "too many requests from this client"))
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// metricsMiddleware counts every HTTP request the server serves.
+func metricsMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		httpRequestsTotal.Inc()
+		c.Next()
+	}
+}
+
+// recoveryMiddleware replaces gin.Recovery: on panic it responds with a
+// JSON-RPC internal-error payload instead of a bare 500, and forwards the
+// panic value and stack trace to the pluggable OnPanicNotify hook so
+// operators can wire alerting without patching this middleware.
+func (s *UnifiedMCPServer) recoveryMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		defer func() {
+			if r := recover(); r != nil {
+				stack := debug.Stack()
+				logrus.Errorf("panic recovered: %v\n%s", r, stack)
+
+				if s.onPanicNotify != nil {
+					s.onPanicNotify(c.Request.Context(), r, stack)
+				}
+
+				c.JSON(http.StatusInternalServerError, s.buildJSONRPCError(nil, -32603, "Internal error", fmt.Sprintf("%v", r)))
+				c.Abort()
+			}
+		}()
+		c.Next()
+	}
+}
+
+// registerPprof mounts net/http/pprof's debug endpoints under /debug/pprof.
+// Only called when pprof is enabled, since it exposes process internals.
+func registerPprof(router *gin.Engine) {
+	router.Any("/debug/pprof", gin.WrapH(http.DefaultServeMux))
+	router.Any("/debug/pprof/*path", gin.WrapH(http.DefaultServeMux))
+}
+
+// SetOnPanicNotify installs a hook invoked with the recovered panic value
+// and stack trace whenever recoveryMiddleware catches a panic, so operators
+// can wire it up to their alerting of choice.
+func (s *UnifiedMCPServer) SetOnPanicNotify(fn func(ctx context.Context, err interface{}, stack []byte)) {
+	s.onPanicNotify = fn
+}
+
+// SetRateLimit reconfigures the per-IP and per-tool token buckets (default
+// is 5 requests/sec with a burst of 10).
+func (s *UnifiedMCPServer) SetRateLimit(everyRPS float64, burst int) {
+	s.ipLimiter = newRateLimiter(everyRPS, burst)
+	s.toolLimiter = newRateLimiter(everyRPS, burst)
+}
+
+// SetPprofEnabled toggles whether /debug/pprof is mounted.
+func (s *UnifiedMCPServer) SetPprofEnabled(enabled bool) {
+	s.pprofEnabled = enabled
+}
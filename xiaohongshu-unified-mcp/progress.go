@@ -0,0 +1,41 @@
+package main
+
+import "context"
+
+// ProgressEvent is a single "notifications/progress" update emitted by a
+// long-running tool handler while it's being streamed over SSE.
+type ProgressEvent struct {
+	Message string `json:"message"`
+	Percent int    `json:"percent"`
+}
+
+type progressContextKey struct{}
+
+// withProgressChannel attaches a progress channel to ctx so a tool handler
+// can report progress without changing its signature. Only set when the
+// call is being streamed over SSE.
+func withProgressChannel(ctx context.Context, ch chan<- ProgressEvent) context.Context {
+	return context.WithValue(ctx, progressContextKey{}, ch)
+}
+
+// progressChannelFromContext retrieves the progress channel set by
+// withProgressChannel, if any.
+func progressChannelFromContext(ctx context.Context) (chan<- ProgressEvent, bool) {
+	ch, ok := ctx.Value(progressContextKey{}).(chan<- ProgressEvent)
+	return ch, ok
+}
+
+// reportProgress is a convenience a tool handler can call from inside its
+// ctx-bearing implementation; it's a no-op when ctx has no progress
+// channel (e.g. the call wasn't streamed over SSE) and never blocks if the
+// channel happens to be full.
+func reportProgress(ctx context.Context, message string, percent int) {
+	ch, ok := progressChannelFromContext(ctx)
+	if !ok {
+		return
+	}
+	select {
+	case ch <- ProgressEvent{Message: message, Percent: percent}:
+	default:
+	}
+}
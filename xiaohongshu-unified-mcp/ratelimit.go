@@ -0,0 +1,40 @@
+package main
+
+import (
+	"sync"
+
+	"golang.org/x/time/rate"
+)
+
+// rateLimiter is a keyed set of token buckets (one per client IP, or one per
+// tool name), created lazily on first use so callers don't need to know the
+// full key space up front.
+type rateLimiter struct {
+	mu      sync.Mutex
+	every   rate.Limit
+	burst   int
+	buckets map[string]*rate.Limiter
+}
+
+// newRateLimiter builds a rateLimiter allowing everyRPS requests per second
+// per key, with bursts up to burst.
+func newRateLimiter(everyRPS float64, burst int) *rateLimiter {
+	return &rateLimiter{
+		every:   rate.Limit(everyRPS),
+		burst:   burst,
+		buckets: make(map[string]*rate.Limiter),
+	}
+}
+
+// Allow reports whether a request for key may proceed right now.
+func (rl *rateLimiter) Allow(key string) bool {
+	rl.mu.Lock()
+	limiter, ok := rl.buckets[key]
+	if !ok {
+		limiter = rate.NewLimiter(rl.every, rl.burst)
+		rl.buckets[key] = limiter
+	}
+	rl.mu.Unlock()
+
+	return limiter.Allow()
+}
@@ -0,0 +1,34 @@
+package main
+
+// GenerateCoverArgs are the typed arguments for generate_xiaohongshu_cover.
+type GenerateCoverArgs struct {
+	BaseURL         string `json:"baseUrl" desc:"Base URL for the cover generator"`
+	Selector        string `json:"selector" desc:"CSS selector for screenshot"`
+	Image           string `json:"image" desc:"Background image path"`
+	Text            string `json:"text" desc:"Text content to display"`
+	OutputPath      string `json:"output_path" desc:"Output file path"`
+	FontFamily      string `json:"fontFamily" desc:"Font family"`
+	FontSize        int    `json:"fontSize" desc:"Font size"`
+	Color           string `json:"color" desc:"Text color"`
+	BackgroundColor string `json:"backgroundColor" desc:"Background color"`
+	IncludeWeather  bool   `json:"includeWeather" desc:"Inject live weather/AQI/alert context into the cover"`
+}
+
+// CheckLoginStatusArgs are the (empty) typed arguments for check_login_status.
+type CheckLoginStatusArgs struct{}
+
+// PublishContentArgs are the typed arguments for publish_content.
+type PublishContentArgs struct {
+	Title   string   `json:"title" validate:"required" desc:"Content title"`
+	Content string   `json:"content" validate:"required" desc:"Content body"`
+	Images  []string `json:"images" validate:"required,min=1" desc:"Image paths"`
+}
+
+// ListFeedsArgs are the (empty) typed arguments for list_feeds.
+type ListFeedsArgs struct{}
+
+// SearchFeedsArgs are the typed arguments for search_feeds.
+type SearchFeedsArgs struct {
+	Keyword string `json:"keyword" validate:"required" desc:"Search keyword"`
+	Limit   int    `json:"limit" validate:"omitempty,min=1,max=100" desc:"Maximum number of results"`
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+var validate = validator.New()
+
+// registeredTool is the type-erased form of a registerTool[T] registration,
+// so UnifiedMCPServer can keep every tool in one map keyed by name and drive
+// both tools/list and tools/call off it.
+type registeredTool struct {
+	name        string
+	description string
+	inputSchema map[string]interface{}
+	call        func(ctx context.Context, rawArgs map[string]interface{}) (*MCPToolResult, *JSONRPCError)
+}
+
+// registerTool builds a registeredTool around a typed handler fn. rawArgs
+// (the JSON-RPC request's "arguments" object) is unmarshalled into T and
+// validated via its `validate` tags before fn ever runs, so fn can trust its
+// argument struct instead of re-deriving defaults and panicking on missing
+// or malformed fields the way the old map[string]interface{} handlers did.
+func registerTool[T any](name, description string, fn func(ctx context.Context, args T) (*MCPToolResult, error)) *registeredTool {
+	return &registeredTool{
+		name:        name,
+		description: description,
+		inputSchema: schemaFor[T](),
+		call: func(ctx context.Context, rawArgs map[string]interface{}) (*MCPToolResult, *JSONRPCError) {
+			var args T
+
+			data, err := json.Marshal(rawArgs)
+			if err != nil {
+				return nil, &JSONRPCError{Code: -32602, Message: "Invalid params", Data: err.Error()}
+			}
+			if err := json.Unmarshal(data, &args); err != nil {
+				return nil, &JSONRPCError{Code: -32602, Message: "Invalid params", Data: err.Error()}
+			}
+			if err := validate.Struct(args); err != nil {
+				return nil, &JSONRPCError{Code: -32602, Message: "Invalid params", Data: fieldErrors(err)}
+			}
+
+			result, err := fn(ctx, args)
+			if err != nil {
+				return nil, &JSONRPCError{Code: -32000, Message: "Tool execution failed", Data: err.Error()}
+			}
+			return result, nil
+		},
+	}
+}
+
+// fieldErrors converts validator.ValidationErrors into a field-name ->
+// reason map, giving clients a machine-readable per-field payload instead of
+// one opaque validation message.
+func fieldErrors(err error) map[string]string {
+	verrs, ok := err.(validator.ValidationErrors)
+	if !ok {
+		return map[string]string{"_": err.Error()}
+	}
+
+	out := make(map[string]string, len(verrs))
+	for _, fe := range verrs {
+		out[fe.Field()] = fmt.Sprintf("failed on the '%s' tag", fe.Tag())
+	}
+	return out
+}
+
+// schemaFor reflects T's `json`, `validate`, and `desc` tags into a JSON
+// Schema object, so tools/list no longer hand-maintains a schema in
+// parallel with the handler's actual argument struct.
+func schemaFor[T any]() map[string]interface{} {
+	var zero T
+	t := reflect.TypeOf(zero)
+
+	properties := map[string]interface{}{}
+	var required []string
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if name == "" || name == "-" {
+			continue
+		}
+
+		properties[name] = map[string]interface{}{
+			"type":        schemaType(field.Type),
+			"description": field.Tag.Get("desc"),
+		}
+
+		if strings.Contains(field.Tag.Get("validate"), "required") {
+			required = append(required, name)
+		}
+	}
+
+	schema := map[string]interface{}{
+		"type":       "object",
+		"properties": properties,
+	}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+// schemaType maps a Go struct field's kind to its JSON Schema type name.
+func schemaType(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	default:
+		return "object"
+	}
+}
+
+// buildToolRegistry wires every tool this server exposes to its typed
+// handler. Call once from NewUnifiedMCPServer.
+func buildToolRegistry(s *UnifiedMCPServer) map[string]*registeredTool {
+	tools := []*registeredTool{
+		registerTool("generate_xiaohongshu_cover", "Generate Xiaohongshu cover image with customizable parameters", s.handleGenerateXiaohongshuCover),
+		registerTool("check_login_status", "Check Xiaohongshu login status", s.handleCheckLoginStatus),
+		registerTool("publish_content", "Publish content to Xiaohongshu", s.handlePublishContent),
+		registerTool("list_feeds", "List Xiaohongshu feeds", s.handleListFeeds),
+		registerTool("search_feeds", "Search Xiaohongshu feeds by keyword", s.handleSearchFeeds),
+	}
+
+	registry := make(map[string]*registeredTool, len(tools))
+	for _, tool := range tools {
+		registry[tool.name] = tool
+	}
+	return registry
+}